@@ -0,0 +1,68 @@
+package main
+
+// Audience is who a greeting without a name is for.
+type Audience int
+
+const (
+	World    Audience = iota // "Hello, World"
+	Everyone                 // a room: "Hello, everyone"
+	Team                     // colleagues: "Hello, team"
+)
+
+// addressees is who to greet when there's no name, by language and
+// audience. A language without a word for an audience greets its World.
+// Each is capitalized as its language writes "Hello, World": "Hola,
+// Mundo", but "Bonjour, le monde" and "Привет, мир".
+var addressees = map[string]map[Audience]string{
+	"English":    {World: "World", Everyone: "everyone", Team: "team"},
+	"Spanish":    {World: "Mundo", Everyone: "todos", Team: "equipo"},
+	"French":     {World: "le monde", Everyone: "tout le monde", Team: "l'équipe"},
+	"German":     {World: "Welt", Everyone: "alle", Team: "Team"},
+	"Portuguese": {World: "Mundo", Everyone: "todos", Team: "equipe"},
+	"Italian":    {World: "Mondo", Everyone: "tutti", Team: "squadra"},
+	"Catalan":    {World: "Món", Everyone: "tothom", Team: "equip"},
+	"Romanian":   {World: "Lume", Everyone: "tuturor", Team: "echipă"},
+	"Dutch":      {World: "Wereld", Everyone: "iedereen", Team: "team"},
+	"Danish":     {World: "Verden", Everyone: "alle", Team: "team"},
+	"Norwegian":  {World: "Verden", Everyone: "alle", Team: "team"},
+	"Swedish":    {World: "Världen", Everyone: "alla", Team: "teamet"},
+	"Finnish":    {World: "maailma", Everyone: "kaikki", Team: "tiimi"},
+	// Polish and Czech address in the vocative.
+	"Polish":     {World: "Świecie", Everyone: "wszystkim", Team: "zespole"},
+	"Czech":      {World: "světe", Everyone: "všem", Team: "týme"},
+	"Hungarian":  {World: "Világ", Everyone: "mindenki", Team: "csapat"},
+	"Turkish":    {World: "Dünya", Everyone: "herkes", Team: "ekip"},
+	"Greek":      {World: "κόσμε"},
+	"Russian":    {World: "мир", Everyone: "все", Team: "команда"},
+	"Ukrainian":  {World: "світе"},
+	"Arabic":     {World: "يا عالم", Everyone: "الجميع"},
+	"Persian":    {World: "دنیا"},
+	"Urdu":       {World: "دنیا"},
+	"Hebrew":     {World: "עולם"},
+	"Hindi":      {World: "दुनिया"},
+	"Bengali":    {World: "বিশ্ব"},
+	"Swahili":    {World: "Dunia", Everyone: "wote"},
+	"Amharic":    {World: "ዓለም"},
+	"Japanese":   {World: "世界", Everyone: "皆さん", Team: "チームの皆さん"},
+	"Chinese":    {World: "世界", Everyone: "大家", Team: "团队"},
+	"Korean":     {World: "세상", Everyone: "여러분", Team: "팀 여러분"},
+	"Thai":       {World: "ชาวโลก"},
+	"Vietnamese": {World: "thế giới", Everyone: "mọi người", Team: "cả nhóm"},
+	"Indonesian": {World: "Dunia", Everyone: "semuanya", Team: "tim"},
+	"Tagalog":    {World: "Mundo", Everyone: "lahat"},
+}
+
+// addressee is who to greet in language in style s when there's no name.
+func addressee(language string, s Style) string {
+	if s.EnglishWorld {
+		return "World"
+	}
+	words, ok := addressees[language]
+	if !ok {
+		words = addressees[english]
+	}
+	if w, ok := words[s.Audience]; ok {
+		return w
+	}
+	return words[World]
+}
@@ -0,0 +1,90 @@
+package main
+
+import "testing"
+
+func TestAddressee(t *testing.T) {
+	cases := []struct {
+		language string
+		want     string
+	}{
+		{"English", "Hello, World"},
+		{"Spanish", "Hola, Mundo"},
+		{"French", "Bonjour, le monde"},
+		{"German", "Hallo, Welt"},
+		{"Portuguese", "Olá, Mundo"},
+		{"Italian", "Salve, Mondo"},
+		{"Catalan", "Hola, Món"},
+		{"Romanian", "Bună ziua, Lume"},
+		{"Dutch", "Hallo, Wereld"},
+		{"Danish", "Hej, Verden"},
+		{"Norwegian", "Hei, Verden"},
+		{"Swedish", "Hej, Världen"},
+		{"Finnish", "Hei, maailma"},
+		{"Polish", "Dzień dobry, Świecie"},
+		{"Czech", "Dobrý den, světe"},
+		{"Hungarian", "Jó napot, Világ"},
+		{"Turkish", "Merhaba, Dünya"},
+		{"Greek", "Γεια σας, κόσμε"},
+		{"Russian", "Здравствуйте, мир"},
+		{"Ukrainian", "Добрий день, світе"},
+		{"Arabic", "مرحبا، يا عالم"},
+		{"Persian", "سلام، دنیا"},
+		{"Urdu", "سلام، دنیا"},
+		{"Hebrew", "שלום, עולם"},
+		{"Hindi", "नमस्ते, दुनिया"},
+		{"Bengali", "নমস্কার, বিশ্ব"},
+		{"Swahili", "Habari, Dunia"},
+		{"Amharic", "ሰላም፣ ዓለም"},
+		{"Japanese", "こんにちは、世界"},
+		{"Chinese", "你好，世界"},
+		{"Korean", "안녕하세요, 세상"},
+		{"Thai", "สวัสดี ชาวโลก"},
+		{"Vietnamese", "Xin chào, thế giới"},
+		{"Indonesian", "Halo, Dunia"},
+		{"Tagalog", "Kumusta, Mundo"},
+		{"Klingon", "Hello, World"},
+	}
+	if len(cases)-1 != len(languages) || len(addressees) != len(languages) {
+		t.Errorf("testing %d addressees of %d for %d languages", len(cases)-1, len(addressees), len(languages))
+	}
+	for _, c := range cases {
+		t.Run(c.language, func(t *testing.T) {
+			assertCorrectMessage(t, Hello("", c.language), c.want)
+			assertCorrectMessage(t, Greet("", c.language, Style{}), c.want)
+		})
+	}
+}
+
+func TestAudience(t *testing.T) {
+	cases := []struct {
+		language string
+		audience Audience
+		want     string
+	}{
+		{"English", Everyone, "Hello, everyone!"},
+		{"English", Team, "Hello, team!"},
+		{"Spanish", Everyone, "¡Hola, todos!"},
+		{"French", Everyone, "Bonjour, tout le monde\u202f!"},
+		{"French", Team, "Bonjour, l'équipe\u202f!"},
+		{"Japanese", Everyone, "こんにちは、皆さん！"},
+		{"Greek", Team, "Γεια σας, κόσμε!"}, // no word for a team: World
+		{"Klingon", Team, "Hello, team!"},
+	}
+	for _, c := range cases {
+		assertCorrectMessage(t, Greet("", c.language, Style{Mark: Exclamation, Audience: c.audience}), c.want)
+	}
+	assertCorrectMessage(t, Greet("Max", "English", Style{Audience: Team}), "Hello, Max")
+}
+
+func TestEnglishWorld(t *testing.T) {
+	old := Style{EnglishWorld: true}
+	assertCorrectMessage(t, Greet("", spanish, old), "Hola, World")
+	assertCorrectMessage(t, Greet("", french, Style{Audience: Everyone, EnglishWorld: true}), "Bonjour, World")
+	assertCorrectMessage(t, SpeakHello(Recipient{}, french, old),
+		`<speak version="1.1" xmlns="http://www.w3.org/2001/10/synthesis" xml:lang="fr"><s>Bonjour, World</s></speak>`)
+
+	// The option is the caller's: others, cached or not, still translate.
+	assertCorrectMessage(t, CachedGreet("", french, old), "Bonjour, World")
+	assertCorrectMessage(t, CachedHello("", french), "Bonjour, le monde")
+	assertCorrectMessage(t, Hello("", spanish), "Hola, Mundo")
+}
@@ -0,0 +1,11 @@
+package main
+
+import "learn-go/internal/braille"
+
+// HelloBraille greets name in language like Hello, in Unicode braille
+// for a refreshable braille display. It returns an error for a language
+// without a braille table for grade.
+func HelloBraille(name, language string, grade braille.Grade) (string, error) {
+	language = resolveLanguage(language)
+	return braille.Render(Hello(name, language), languages[language].Tag, grade)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"learn-go/internal/braille"
+)
+
+func TestHelloBraille(t *testing.T) {
+	cases := []struct {
+		name, language string
+		grade          braille.Grade
+		want           string
+	}{
+		{"", "English", braille.Grade1, "⠠⠓⠑⠇⠇⠕⠂⠀⠠⠺⠕⠗⠇⠙"},
+		{"", "English", braille.Grade2, "⠠⠓⠑⠇⠇⠕⠂⠀⠠⠸⠺"},
+		{"Max", "en", braille.Grade2, "⠠⠓⠑⠇⠇⠕⠂⠀⠠⠍⠁⠭"},
+		{"", "Spanish", braille.Grade1, "⠨⠓⠕⠇⠁⠂⠀⠨⠍⠥⠝⠙⠕"},
+		{"Élodie", "français", braille.Grade1, "⠨⠃⠕⠝⠚⠕⠥⠗⠂⠀⠨⠿⠇⠕⠙⠊⠑"},
+	}
+	for _, c := range cases {
+		got, err := HelloBraille(c.name, c.language, c.grade)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertCorrectMessage(t, got, c.want)
+	}
+
+	if _, err := HelloBraille("Max", "French", braille.Grade2); err == nil {
+		t.Error("want an error for French Grade 2")
+	}
+	if _, err := HelloBraille("Max", "Chinese", braille.Grade1); err == nil {
+		t.Error("want an error for Chinese")
+	}
+}
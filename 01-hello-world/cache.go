@@ -0,0 +1,72 @@
+package main
+
+import (
+	"time"
+
+	"learn-go/internal/cache"
+)
+
+// greetingKey is everything a greeting depends on besides the catalog:
+// the name, the language and every option of the function that built
+// it.
+type greetingKey struct {
+	name, language string
+	style          Style
+	// welcome is set for HelloTo's greetings, which also depend on the
+	// recipient's form.
+	welcome bool
+	form    Form
+}
+
+type cachedGreeting struct {
+	text     string
+	fallback bool
+}
+
+// greetingCache holds the greetings of the names services ask for again
+// and again. Entries expire so that a catalog changed behind our back,
+// without useCatalog, is picked up eventually.
+var greetingCache = cache.New[greetingKey, cachedGreeting](cache.Options{
+	MaxEntries: 10_000,
+	TTL:        10 * time.Minute,
+})
+
+// CachedHello is Hello for callers that greet the same names often.
+// Concurrent calls for a greeting that isn't cached yet build it once.
+func CachedHello(name, language string) string {
+	return CachedGreet(name, language, Style{})
+}
+
+// CachedGreet is Greet for callers that greet the same names often.
+func CachedGreet(name, language string, s Style) string {
+	language = resolveLanguage(language)
+	return fromCache(greetingKey{name: name, language: language, style: s}, func() (string, bool) {
+		return greet(name, language, s)
+	})
+}
+
+// CachedHelloTo is HelloTo for callers that welcome the same recipients
+// often.
+func CachedHelloTo(r Recipient, language string, m Mark) string {
+	language = resolveLanguage(language)
+	k := greetingKey{name: r.Name, language: language, style: Style{Mark: m}, welcome: true, form: r.Form}
+	return fromCache(k, func() (string, bool) {
+		return welcomeTo(r, language, m)
+	})
+}
+
+// fromCache returns the greeting of k, building it if it isn't
+// cached, and counts it.
+func fromCache(k greetingKey, build func() (text string, fallback bool)) string {
+	g, _ := greetingCache.GetOrLoad(k, func() (cachedGreeting, error) {
+		text, fallback := build()
+		return cachedGreeting{text, fallback}, nil
+	})
+	recordGreeting(k.language, g.fallback)
+	return g.text
+}
+
+// GreetingCacheStats reports the hits and misses of the cached greetings.
+func GreetingCacheStats() cache.Stats {
+	return greetingCache.Stats()
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"learn-go/internal/catalog"
+)
+
+func TestCachedHello(t *testing.T) {
+	saved := currentCatalog()
+	t.Cleanup(func() { useCatalog(saved) })
+	useCatalog(saved)
+	before := GreetingCacheStats()
+
+	assertCorrectMessage(t, CachedHello("Elodie", spanish), "Hola, Elodie")
+	assertCorrectMessage(t, CachedHello("Elodie", spanish), "Hola, Elodie")
+	assertCorrectMessage(t, CachedHello("", french), "Bonjour, le monde")
+
+	st := GreetingCacheStats()
+	if hits, misses := st.Hits-before.Hits, st.Misses-before.Misses; hits != 1 || misses != 2 {
+		t.Errorf("got %d hits, %d misses want 1, 2", hits, misses)
+	}
+
+	t.Run("catalog reload", func(t *testing.T) {
+		useCatalog(catalog.Map{spanish: "¡Hola, "})
+		assertCorrectMessage(t, CachedHello("Elodie", spanish), "¡Hola, Elodie")
+	})
+}
+
+// TestCachedOptions greets one name in one language every way there is:
+// none of the greetings may be served for another.
+func TestCachedOptions(t *testing.T) {
+	cases := []struct {
+		name string
+		get  func() string
+		want string
+	}{
+		{"Hello", func() string { return CachedHello("", spanish) }, "Hola, Mundo"},
+		{"formal", func() string { return CachedGreet("", spanish, Style{Register: Formal}) }, "Saludos, Mundo"},
+		{"mark", func() string { return CachedGreet("", spanish, Style{Mark: Exclamation}) }, "¡Hola, Mundo!"},
+		{"audience", func() string { return CachedGreet("", spanish, Style{Audience: Everyone}) }, "Hola, todos"},
+		{"welcome", func() string { return CachedHelloTo(Recipient{}, spanish, NoMark) }, "Te damos la bienvenida, Mundo"},
+		{"form", func() string { return CachedHelloTo(Recipient{Form: Feminine}, spanish, NoMark) }, "Bienvenida, Mundo"},
+	}
+	for range 2 {
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				assertCorrectMessage(t, c.get(), c.want)
+			})
+		}
+	}
+}
+
+// BenchmarkCachedHello greets a few thousand names from every goroutine.
+// Compare -cpu=1,4,16 to see how much the goroutines wait for each other.
+func BenchmarkCachedHello(b *testing.B) {
+	names := make([]string, 2000)
+	for i := range names {
+		names[i] = fmt.Sprintf("user%d", i)
+	}
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			CachedHello(names[i%len(names)], spanish)
+			i++
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"learn-go/internal/catalog"
+)
+
+// CatalogStore looks up the greeting prefix of a language. catalog.Map
+// keeps them in memory and catalog.SQL in a database. A language's
+// neutral greeting is under its name, and its other registers under
+// names like "Spanish/formal": see catalogKey.
+type CatalogStore interface {
+	Prefix(ctx context.Context, language string) (prefix string, ok bool, err error)
+}
+
+// catalogStore is where every greeting comes from. A store that fails
+// or doesn't know the language gets an English greeting rather than
+// none.
+var (
+	catalogMu    sync.RWMutex
+	catalogStore CatalogStore = defaultCatalog()
+)
+
+// defaultCatalog is the catalog seeded from languages: every register of
+// every language Hello knows.
+func defaultCatalog() catalog.Map {
+	m := catalog.Map{}
+	for _, l := range languages {
+		for _, r := range []Register{Neutral, Formal, Informal} {
+			m[catalogKey(l.Name, r)] = l.GreetingIn(r) + l.Comma
+		}
+	}
+	return m
+}
+
+// catalogKey is where the catalog keeps the greeting of language in
+// register r: the neutral one under the language's name, as it always
+// has, and the others under names like "Spanish/formal".
+func catalogKey(language string, r Register) string {
+	if r == Neutral {
+		return language
+	}
+	return language + "/" + r.String()
+}
+
+// lookupGreeting returns the greeting prefix of language in register r
+// from the catalog, falling back to the language's neutral greeting, and
+// the language to punctuate it with. A language the catalog doesn't have
+// is greeted in English, and known is false. A language only the catalog
+// has is punctuated like English.
+func lookupGreeting(language string, r Register) (l Language, prefix string, known bool) {
+	if prefix, ok := catalogPrefix(language, r); ok {
+		l, ok := languages[language]
+		if !ok {
+			l = Language{Name: language, Tag: "und", Script: "Zyyy", Punctuation: latin}
+		}
+		return l, prefix, true
+	}
+	prefix, ok := catalogPrefix(english, r)
+	if !ok {
+		prefix = englishHelloPrefix
+	}
+	return languages[english], prefix, language == english
+}
+
+func catalogPrefix(language string, r Register) (string, bool) {
+	ctx := context.Background()
+	s := currentCatalog()
+	prefix, ok, err := s.Prefix(ctx, catalogKey(language, r))
+	if err == nil && !ok && r != Neutral {
+		prefix, ok, err = s.Prefix(ctx, language)
+	}
+	return prefix, ok && err == nil
+}
+
+func currentCatalog() CatalogStore {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	return catalogStore
+}
+
+// useCatalog makes s the catalog, for instance after reloading it, and
+// drops the cached greetings built from the old one.
+func useCatalog(s CatalogStore) {
+	catalogMu.Lock()
+	catalogStore = s
+	catalogMu.Unlock()
+	greetingCache.Purge()
+}
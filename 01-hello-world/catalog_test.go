@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"learn-go/internal/catalog"
+)
+
+// brokenStore can't reach its database.
+type brokenStore struct{}
+
+func (brokenStore) Prefix(ctx context.Context, language string) (string, bool, error) {
+	return "", false, errors.New("connection refused")
+}
+
+func TestCatalogStore(t *testing.T) {
+	saved := currentCatalog()
+	t.Cleanup(func() { useCatalog(saved) })
+
+	useCatalog(catalog.Map{english: englishHelloPrefix, "Klingon": "nuqneH, "})
+	assertCorrectMessage(t, Hello("Worf", "Klingon"), "nuqneH, Worf")
+	assertCorrectMessage(t, Hello("Elodie", spanish), "Hello, Elodie")
+
+	useCatalog(brokenStore{})
+	assertCorrectMessage(t, Hello("Elodie", spanish), "Hello, Elodie")
+}
+
+// TestEveryGreetingFromCatalog checks that every way of greeting reads
+// the catalog, with languages only for punctuation.
+func TestEveryGreetingFromCatalog(t *testing.T) {
+	saved := currentCatalog()
+	t.Cleanup(func() { useCatalog(saved) })
+
+	useCatalog(catalog.Map{
+		english:          englishHelloPrefix,
+		"Spanish":        "Buenas, ",
+		"Spanish/formal": "Buenas tardes, ",
+		"Klingon":        "nuqneH, ",
+	})
+	r := Recipient{Name: "Max"}
+	assertCorrectMessage(t, Hello("Max", spanish), "Buenas, Max")
+	assertCorrectMessage(t, CachedHello("Max", spanish), "Buenas, Max")
+	assertCorrectMessage(t, Greet("Max", spanish, Style{Mark: Exclamation}), "¡Buenas, Max!")
+	assertCorrectMessage(t, HelloFormal("Max", spanish), "Buenas tardes, Max")
+	assertCorrectMessage(t, HelloInformal("Max", spanish), "Buenas, Max") // no informal entry
+	assertCorrectMessage(t, HelloFormal("Max", french), "Hello, Max")     // not in the catalog
+	assertCorrectMessage(t, Greet("Worf", "Klingon", Style{Mark: Question}), "nuqneH, Worf?")
+	assertCorrectMessage(t, HelloTo(Recipient{Name: "Worf"}, "Klingon", Exclamation), "nuqneH, Worf!")
+	assertCorrectMessage(t, HTMLHello(r, spanish, Style{}),
+		`<p role="status" aria-live="polite" lang="es" dir="ltr">Buenas, <span translate="no">Max</span></p>`)
+	assertCorrectMessage(t, SpeakHello(r, spanish, Style{Register: Formal}),
+		`<speak version="1.1" xmlns="http://www.w3.org/2001/10/synthesis" xml:lang="es"><s>Buenas tardes, Max</s></speak>`)
+}
+
+// helloMax is how Hello and Greet greet Max in each language of the
+// default catalog, by register: neutral, formal and informal.
+var helloMax = map[string][3]string{
+	"Amharic":    {"ሰላም፣ Max", "ጤና ይስጥልኝ፣ Max", "ሰላም፣ Max"},
+	"Arabic":     {"مرحبا، Max", "السلام عليكم، Max", "أهلا، Max"},
+	"Bengali":    {"নমস্কার, Max", "নমস্কার, Max", "হ্যালো, Max"},
+	"Catalan":    {"Hola, Max", "Hola, Max", "Hola, Max"},
+	"Chinese":    {"你好，Max", "您好，Max", "嗨，Max"},
+	"Czech":      {"Dobrý den, Max", "Dobrý den, Max", "Ahoj, Max"},
+	"Danish":     {"Hej, Max", "Goddag, Max", "Hej, Max"},
+	"Dutch":      {"Hallo, Max", "Goedendag, Max", "Hoi, Max"},
+	"English":    {"Hello, Max", "Good day, Max", "Hi, Max"},
+	"Finnish":    {"Hei, Max", "Hyvää päivää, Max", "Moi, Max"},
+	"French":     {"Bonjour, Max", "Bonjour, Max", "Salut, Max"},
+	"German":     {"Hallo, Max", "Guten Tag, Max", "Hallo, Max"},
+	"Greek":      {"Γεια σας, Max", "Χαίρετε, Max", "Γεια σου, Max"},
+	"Hebrew":     {"שלום, Max", "שלום, Max", "היי, Max"},
+	"Hindi":      {"नमस्ते, Max", "नमस्कार, Max", "नमस्ते, Max"},
+	"Hungarian":  {"Jó napot, Max", "Jó napot kívánok, Max", "Szia, Max"},
+	"Indonesian": {"Halo, Max", "Salam, Max", "Hai, Max"},
+	"Italian":    {"Salve, Max", "Salve, Max", "Ciao, Max"},
+	"Japanese":   {"こんにちは、Max", "こんにちは、Max", "やあ、Max"},
+	"Korean":     {"안녕하세요, Max", "안녕하십니까, Max", "안녕, Max"},
+	"Norwegian":  {"Hei, Max", "God dag, Max", "Hei, Max"},
+	"Persian":    {"سلام، Max", "درود، Max", "سلام، Max"},
+	"Polish":     {"Dzień dobry, Max", "Dzień dobry, Max", "Cześć, Max"},
+	"Portuguese": {"Olá, Max", "Olá, Max", "Oi, Max"},
+	"Romanian":   {"Bună ziua, Max", "Bună ziua, Max", "Salut, Max"},
+	"Russian":    {"Здравствуйте, Max", "Здравствуйте, Max", "Привет, Max"},
+	"Spanish":    {"Hola, Max", "Saludos, Max", "Hola, Max"},
+	"Swahili":    {"Habari, Max", "Hujambo, Max", "Mambo, Max"},
+	"Swedish":    {"Hej, Max", "God dag, Max", "Hej, Max"},
+	"Tagalog":    {"Kumusta, Max", "Magandang araw, Max", "Kumusta, Max"},
+	"Thai":       {"สวัสดี Max", "สวัสดี Max", "หวัดดี Max"},
+	"Turkish":    {"Merhaba, Max", "Merhaba, Max", "Selam, Max"},
+	"Ukrainian":  {"Добрий день, Max", "Добрий день, Max", "Привіт, Max"},
+	"Urdu":       {"سلام، Max", "السلام علیکم، Max", "سلام، Max"},
+	"Vietnamese": {"Xin chào, Max", "Xin chào, Max", "Chào, Max"},
+}
+
+// TestEveryCatalogLanguage greets Max in every language and register of
+// the catalog. It fails for an entry no test greets, and `learn
+// coverage` fails the chapter with it.
+func TestEveryCatalogLanguage(t *testing.T) {
+	registers := map[string]Register{}
+	for _, r := range []Register{Neutral, Formal, Informal} {
+		registers[catalogKey("", r)] = r
+	}
+	for _, key := range slices.Sorted(maps.Keys(defaultCatalog())) {
+		t.Run(key, func(t *testing.T) {
+			language, _, _ := strings.Cut(key, "/")
+			r, ok := registers[strings.TrimPrefix(key, language)]
+			if !ok {
+				t.Fatalf("%s isn't in any register", key)
+			}
+			want, ok := helloMax[language]
+			if !ok {
+				t.Fatalf("%s is in the catalog but no test greets it: add it to helloMax", language)
+			}
+			assertCorrectMessage(t, Greet("Max", language, Style{Register: r}), want[r])
+			if r == Neutral {
+				assertCorrectMessage(t, Hello("Max", language), want[r])
+			}
+		})
+	}
+	for language := range helloMax {
+		if _, ok := languages[language]; !ok {
+			t.Errorf("helloMax greets %s, which isn't in the catalog", language)
+		}
+	}
+}
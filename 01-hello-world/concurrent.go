@@ -0,0 +1,21 @@
+package main
+
+import (
+	"context"
+
+	concurrency "learn-go/03-concurrency"
+)
+
+// HelloAll greets every name in language, at most workers at a time, and
+// returns the greetings in the order of names.
+func HelloAll(ctx context.Context, names []string, language string, workers int) ([]string, error) {
+	greetings := make([]string, len(names))
+	err := concurrency.ForEach(ctx, names, workers, func(ctx context.Context, i int, name string) error {
+		greetings[i] = Hello(name, language)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return greetings, nil
+}
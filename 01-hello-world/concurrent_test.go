@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHelloAll(t *testing.T) {
+	names := []string{"Elodie", "James", "", "Max"}
+	got, err := HelloAll(context.Background(), names, "Spanish", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCorrectMessage(t, strings.Join(got, "; "), "Hola, Elodie; Hola, James; Hola, Mundo; Hola, Max")
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := HelloAll(ctx, names, "French", 2); !errors.Is(err, context.Canceled) {
+			t.Errorf("got error %v want %v", err, context.Canceled)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+
+	"learn-go/internal/width"
+)
+
+// ellipses mark a name cut short where "…" isn't the convention: Chinese
+// uses two, each as wide as a character.
+var ellipses = map[string]string{
+	"Chinese": "……",
+}
+
+// HelloFit greets name in language like Hello, in at most maxCells cells
+// of a fixed-width display. A name that doesn't fit is cut short between
+// two grapheme clusters, so an accent or an emoji is never split, and
+// ends with the language's ellipsis.
+func HelloFit(name, language string, maxCells int) string {
+	language = resolveLanguage(language)
+	greeting := Hello(name, language)
+
+	m := width.Measure{EastAsian: eastAsian(language)}
+	ellipsis, ok := ellipses[language]
+	if !ok {
+		ellipsis = "…"
+	}
+	if m.String(greeting) <= maxCells {
+		return greeting
+	}
+	if name == "" {
+		name = addressee(language, Style{})
+	}
+	prefix := strings.TrimSuffix(greeting, name)
+	room := maxCells - m.String(prefix)
+	if room <= m.String(ellipsis) {
+		// Not even one cluster of the name fits.
+		return m.Truncate(greeting, maxCells, ellipsis)
+	}
+	return prefix + m.Truncate(name, room, ellipsis)
+}
+
+// eastAsian reports whether language is displayed with East Asian fonts,
+// where ambiguous characters such as "…" are wide.
+func eastAsian(language string) bool {
+	switch languages[language].Script {
+	case "Hans", "Hant", "Jpan", "Kore":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	"learn-go/internal/width"
+)
+
+func TestHelloFit(t *testing.T) {
+	cases := []struct {
+		name, language string
+		maxCells       int
+		want           string
+	}{
+		{"Max", "English", 20, "Hello, Max"},
+		{"Max", "English", 10, "Hello, Max"},
+		{"Maximilian", "English", 12, "Hello, Maxi…"},
+		{"Élodie", "French", 12, "Bonjour, Él…"},
+		{"Zoë 👨‍👩‍👧 Smith", "English", 13, "Hello, Zoë …"},
+		{"Zoë 👨‍👩‍👧 Smith", "English", 14, "Hello, Zoë 👨‍👩‍👧…"},
+		{"🇫🇷🇯🇵🇩🇪", "English", 12, "Hello, 🇫🇷🇯🇵…"},
+		{"欧阳小明", "Chinese", 12, "你好，欧……"},
+		{"山田太郎", "Japanese", 16, "こんにちは、山…"},
+		{"Maximilian", "English", 8, "Hello, …"},
+		{"Maximilian", "English", 5, "Hell…"},
+		{"", "Spanish", 8, "Hola, M…"},
+	}
+	for _, c := range cases {
+		got := HelloFit(c.name, c.language, c.maxCells)
+		assertCorrectMessage(t, got, c.want)
+		m := width.Measure{EastAsian: eastAsian(c.language)}
+		if w := m.String(got); w > c.maxCells {
+			t.Errorf("HelloFit(%q, %q, %d) takes %d cells", c.name, c.language, c.maxCells, w)
+		}
+	}
+}
@@ -2,35 +2,35 @@ package main
 
 import (
 	"fmt"
+	"os"
 )
 
 const (
+	english = "English"
 	spanish = "Spanish"
 	french  = "French"
 
 	englishHelloPrefix = "Hello, "
-	spanishHelloPrefix = "Hola, "
-	frenchHelloPrefix  = "Bonjour, "
 )
 
 func Hello(name, language string) string {
-	if name == "" {
-		name = "World"
-	}
-
-	return greetingPrefix(language) + name
+	language = resolveLanguage(language)
+	greeting, fallback := greet(name, language, Style{})
+	recordGreeting(language, fallback)
+	return greeting
 }
 
-func greetingPrefix(language string) (prefix string) {
-	switch language {
-	case spanish:
-		return spanishHelloPrefix
-	case french:
-		return frenchHelloPrefix
-	}
-	return englishHelloPrefix
+// greet builds the greeting in style s and reports whether language fell
+// back to English.
+func greet(name, language string, s Style) (greeting string, fallback bool) {
+	_, sn, known := greetingSentence(Recipient{Name: name}, language, s)
+	return sn.String(), !known
 }
 
 func main() {
 	fmt.Println(Hello("Max", "English"))
+
+	if err := flushGreetingStats(); err != nil {
+		fmt.Fprintln(os.Stderr, "saving greeting stats:", err)
+	}
 }
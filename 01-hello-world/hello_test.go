@@ -34,3 +34,9 @@ func assertCorrectMessage(t testing.TB, got, want string) {
 		t.Errorf("got %q want %q", got, want)
 	}
 }
+
+func BenchmarkHello(b *testing.B) {
+	for b.Loop() {
+		Hello("Elodie", "Spanish")
+	}
+}
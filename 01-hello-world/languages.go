@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+
+	"learn-go/internal/langdb"
+)
+
+// Register is how familiar a greeting is.
+type Register int
+
+const (
+	Neutral  Register = iota // fine for anyone
+	Formal                   // strangers, elders, customers
+	Informal                 // friends and family
+)
+
+var registerNames = []string{"neutral", "formal", "informal"}
+
+func (r Register) String() string {
+	if r < 0 || int(r) >= len(registerNames) {
+		return fmt.Sprintf("Register(%d)", int(r))
+	}
+	return registerNames[r]
+}
+
+// Punctuation is how a language punctuates a greeting.
+type Punctuation struct {
+	Comma       string // between the greeting and the name, spacing included
+	Exclamation string
+	Question    string
+	// OpenExclamation and OpenQuestion start a sentence that ends with an
+	// exclamation or a question, as Spanish "¡" and "¿" do.
+	OpenExclamation string
+	OpenQuestion    string
+	// MarkSpace goes before the exclamation and question marks, as the
+	// narrow no-break space does in French.
+	MarkSpace string
+}
+
+// Language is how to greet someone in one language. The greetings seed
+// the catalog, which is where greetings are read from: see
+// lookupGreeting.
+type Language struct {
+	Name     string // in English, as passed to Hello
+	Tag      string // BCP 47
+	Script   string // ISO 15924
+	Greeting string // the Neutral form
+	Formal   string
+	Informal string
+	Punctuation
+	Source string // where the greetings were checked
+}
+
+// GreetingIn returns the seed greeting of register r.
+func (l Language) GreetingIn(r Register) string {
+	switch r {
+	case Formal:
+		return l.Formal
+	case Informal:
+		return l.Informal
+	}
+	return l.Greeting
+}
+
+// RightToLeft reports whether the language's script is written from right
+// to left.
+func (l Language) RightToLeft() bool {
+	switch l.Script {
+	case "Arab", "Hebr", "Thaa", "Syrc":
+		return true
+	}
+	return false
+}
+
+// Common punctuation.
+var (
+	latin = Punctuation{Comma: ", ", Exclamation: "!", Question: "?"}
+	// Arabic script has its own comma and question mark.
+	arabic = Punctuation{Comma: "، ", Exclamation: "!", Question: "؟"}
+	// Chinese and Japanese use full-width marks without spaces.
+	chinese  = Punctuation{Comma: "，", Exclamation: "！", Question: "？"}
+	japanese = Punctuation{Comma: "、", Exclamation: "！", Question: "？"}
+)
+
+// languages is every language Hello knows, by English name, and the seed
+// of the catalog. Every greeting can be said all day to anyone its
+// register suits: no "good morning", no leave-takings and nothing only
+// for elders. Source is the English Wiktionary entries the greetings and
+// their registers were checked against, as CLDR has no greetings; a
+// change to a greeting needs one too.
+var languages = indexLanguages([]Language{
+	{"English", "en", "Latn", "Hello", "Good day", "Hi", latin, "Wiktionary: hello, good day, hi"},
+	{"Spanish", "es", "Latn", "Hola", "Saludos", "Hola", Punctuation{Comma: ", ", Exclamation: "!", Question: "?", OpenExclamation: "¡", OpenQuestion: "¿"}, "Wiktionary: hola, saludos"},
+	{"French", "fr", "Latn", "Bonjour", "Bonjour", "Salut", Punctuation{Comma: ", ", Exclamation: "!", Question: "?", MarkSpace: "\u202f"}, "Wiktionary: bonjour, salut"},
+	{"German", "de", "Latn", "Hallo", "Guten Tag", "Hallo", latin, "Wiktionary: hallo, guten Tag"},
+	{"Portuguese", "pt", "Latn", "Olá", "Olá", "Oi", latin, "Wiktionary: olá, oi"},
+	{"Italian", "it", "Latn", "Salve", "Salve", "Ciao", latin, "Wiktionary: salve, ciao"},
+	{"Catalan", "ca", "Latn", "Hola", "Hola", "Hola", latin, "Wiktionary: hola"},
+	{"Romanian", "ro", "Latn", "Bună ziua", "Bună ziua", "Salut", latin, "Wiktionary: bună ziua, salut"},
+	{"Dutch", "nl", "Latn", "Hallo", "Goedendag", "Hoi", latin, "Wiktionary: hallo, goedendag, hoi"},
+	{"Danish", "da", "Latn", "Hej", "Goddag", "Hej", latin, "Wiktionary: hej, goddag"},
+	{"Norwegian", "nb", "Latn", "Hei", "God dag", "Hei", latin, "Wiktionary: hei, god dag"},
+	{"Swedish", "sv", "Latn", "Hej", "God dag", "Hej", latin, "Wiktionary: hej, god dag"},
+	{"Finnish", "fi", "Latn", "Hei", "Hyvää päivää", "Moi", latin, "Wiktionary: hei, hyvää päivää, moi"},
+	{"Polish", "pl", "Latn", "Dzień dobry", "Dzień dobry", "Cześć", latin, "Wiktionary: dzień dobry, cześć"},
+	{"Czech", "cs", "Latn", "Dobrý den", "Dobrý den", "Ahoj", latin, "Wiktionary: dobrý den, ahoj"},
+	{"Hungarian", "hu", "Latn", "Jó napot", "Jó napot kívánok", "Szia", latin, "Wiktionary: jó napot, jó napot kívánok, szia"},
+	{"Turkish", "tr", "Latn", "Merhaba", "Merhaba", "Selam", latin, "Wiktionary: merhaba, selam"},
+	// Greek asks questions with ";", which looks like a semicolon.
+	{"Greek", "el", "Grek", "Γεια σας", "Χαίρετε", "Γεια σου", Punctuation{Comma: ", ", Exclamation: "!", Question: ";"}, "Wiktionary: γεια σας, χαίρετε, γεια σου"},
+	{"Russian", "ru", "Cyrl", "Здравствуйте", "Здравствуйте", "Привет", latin, "Wiktionary: здравствуйте, привет"},
+	{"Ukrainian", "uk", "Cyrl", "Добрий день", "Добрий день", "Привіт", latin, "Wiktionary: добрий день, привіт"},
+	{"Arabic", "ar", "Arab", "مرحبا", "السلام عليكم", "أهلا", arabic, "Wiktionary: مرحبا, السلام عليكم, أهلا"},
+	{"Persian", "fa", "Arab", "سلام", "درود", "سلام", arabic, "Wiktionary: سلام, درود"},
+	{"Urdu", "ur", "Arab", "سلام", "السلام علیکم", "سلام", arabic, "Wiktionary: سلام, السلام علیکم"},
+	{"Hebrew", "he", "Hebr", "שלום", "שלום", "היי", latin, "Wiktionary: שלום, היי"},
+	{"Hindi", "hi", "Deva", "नमस्ते", "नमस्कार", "नमस्ते", latin, "Wiktionary: नमस्ते, नमस्कार"},
+	{"Bengali", "bn", "Beng", "নমস্কার", "নমস্কার", "হ্যালো", latin, "Wiktionary: নমস্কার, হ্যালো"},
+	{"Swahili", "sw", "Latn", "Habari", "Hujambo", "Mambo", latin, "Wiktionary: habari, hujambo, mambo"},
+	{"Amharic", "am", "Ethi", "ሰላም", "ጤና ይስጥልኝ", "ሰላም", Punctuation{Comma: "፣ ", Exclamation: "!", Question: "?"}, "Wiktionary: ሰላም, ጤና ይስጥልኝ"},
+	{"Japanese", "ja", "Jpan", "こんにちは", "こんにちは", "やあ", japanese, "Wiktionary: こんにちは, やあ"},
+	{"Chinese", "zh", "Hans", "你好", "您好", "嗨", chinese, "Wiktionary: 你好, 您好, 嗨"},
+	{"Korean", "ko", "Kore", "안녕하세요", "안녕하십니까", "안녕", latin, "Wiktionary: 안녕하세요, 안녕하십니까, 안녕"},
+	// Thai doesn't use commas: a space separates phrases.
+	{"Thai", "th", "Thai", "สวัสดี", "สวัสดี", "หวัดดี", Punctuation{Comma: " ", Exclamation: "!", Question: "?"}, "Wiktionary: สวัสดี, หวัดดี"},
+	{"Vietnamese", "vi", "Latn", "Xin chào", "Xin chào", "Chào", latin, "Wiktionary: xin chào, chào"},
+	{"Indonesian", "id", "Latn", "Halo", "Salam", "Hai", latin, "Wiktionary: halo, salam, hai"},
+	{"Tagalog", "tl", "Latn", "Kumusta", "Magandang araw", "Kumusta", latin, "Wiktionary: kumusta, magandang araw"},
+})
+
+func indexLanguages(ls []Language) map[string]Language {
+	m := make(map[string]Language, len(ls))
+	for _, l := range ls {
+		m[l.Name] = l
+	}
+	return m
+}
+
+// resolveLanguage returns the English name of language, which may be
+// given by any name or code langdb knows, such as "español" or "es". A
+// language it doesn't know is returned as it is.
+func resolveLanguage(language string) string {
+	if _, ok := languages[language]; ok {
+		return language
+	}
+	if l, ok := langdb.Lookup(language); ok {
+		if _, ok := languages[l.Name]; ok {
+			return l.Name
+		}
+	}
+	return language
+}
+
+// HelloFormal greets name formally in language, or in English if the
+// language is unknown.
+func HelloFormal(name, language string) string {
+	return Greet(name, language, Style{Register: Formal})
+}
+
+// HelloInformal greets name as a friend would in language, or in English
+// if the language is unknown.
+func HelloInformal(name, language string) string {
+	return Greet(name, language, Style{Register: Informal})
+}
@@ -0,0 +1,100 @@
+package main
+
+import "testing"
+
+func TestLanguages(t *testing.T) {
+	cases := []struct {
+		name     string
+		language string
+		register Register
+		want     string
+	}{
+		{"neutral is fine for strangers", "Polish", Neutral, "Dzień dobry, Max"},
+		{"informal is for friends", "Polish", Informal, "Cześć, Max"},
+		{"neutral Italian", "Italian", Neutral, "Salve, Max"},
+		{"informal Italian", "Italian", Informal, "Ciao, Max"},
+		{"neutral Ukrainian", "Ukrainian", Neutral, "Добрий день, Max"},
+		{"informal Ukrainian", "Ukrainian", Informal, "Привіт, Max"},
+		{"formal all day", "Spanish", Formal, "Saludos, Max"},
+		{"formal Portuguese all day", "Portuguese", Formal, "Olá, Max"},
+		{"formal is a greeting, not a goodbye", "Turkish", Formal, "Merhaba, Max"},
+		{"formal is for anyone, not only elders", "Swahili", Formal, "Hujambo, Max"},
+		{"formal differs in the script", "Chinese", Formal, "您好，Max"},
+		{"by code", "hu", Formal, "Jó napot kívánok, Max"},
+		{"unknown language is English formally", "Klingon", Formal, "Good day, Max"},
+		{"unknown language is English informally", "Klingon", Informal, "Hi, Max"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assertCorrectMessage(t, Greet("Max", c.language, Style{Register: c.register}), c.want)
+		})
+	}
+
+	t.Run("helpers", func(t *testing.T) {
+		assertCorrectMessage(t, HelloFormal("", "Klingon"), "Good day, World")
+		assertCorrectMessage(t, HelloInformal("Max", "Czech"), "Ahoj, Max")
+	})
+}
+
+// notGreetings are said at a greeting's place but don't belong in any
+// register of languages.
+var notGreetings = map[string]string{
+	"Buenos días": "good morning", "Buenas tardes": "good afternoon",
+	"Bom dia": "good morning", "Boa tarde": "good afternoon",
+	"Buongiorno": "good morning", "Buonasera": "good evening",
+	"Bon dia": "good morning", "Bonsoir": "good evening",
+	"Guten Morgen": "good morning", "Guten Abend": "good evening",
+	"İyi günler": "mostly said when leaving", "Shikamoo": "only for elders",
+}
+
+func TestGreetingsAllDayForAnyone(t *testing.T) {
+	for name, l := range languages {
+		for _, r := range []Register{Neutral, Formal, Informal} {
+			if why, ok := notGreetings[l.GreetingIn(r)]; ok {
+				t.Errorf("%s: %s greeting %q is %s", name, r, l.GreetingIn(r), why)
+			}
+		}
+	}
+}
+
+func TestLanguageData(t *testing.T) {
+	for name, l := range languages {
+		if l.Tag == "" || len(l.Script) != 4 || l.Greeting == "" || l.Formal == "" || l.Informal == "" {
+			t.Errorf("%s: incomplete: %+v", name, l)
+		}
+		if l.Source == "" {
+			t.Errorf("%s: no source", name)
+		}
+		if l.Comma == "" || l.Exclamation == "" || l.Question == "" {
+			t.Errorf("%s: incomplete punctuation: %+v", name, l.Punctuation)
+		}
+	}
+	if !languages["Arabic"].RightToLeft() || languages["Greek"].RightToLeft() {
+		t.Error("Arabic should be right to left and Greek not")
+	}
+}
+
+func TestLanguageNames(t *testing.T) {
+	cases := []struct {
+		language, want string
+	}{
+		{"español", "Hola, Max"},
+		{"es", "Hola, Max"},
+		{"spa", "Hola, Max"},
+		{"Castilian", "Hola, Max"},
+		{"FRANCAIS", "Bonjour, Max"},
+		{"fr-CA", "Bonjour, Max"},
+		{"日本語", "こんにちは、Max"},
+		{"Farsi", "سلام، Max"},
+		{"tlh", "Hello, Max"},
+	}
+	for _, c := range cases {
+		t.Run(c.language, func(t *testing.T) {
+			assertCorrectMessage(t, Hello("Max", c.language), c.want)
+			assertCorrectMessage(t, CachedHello("Max", c.language), c.want)
+			assertCorrectMessage(t, Greet("Max", c.language, Style{}), c.want)
+		})
+	}
+	assertCorrectMessage(t, Hello("", "de"), "Hallo, Welt")
+	assertCorrectMessage(t, HelloTo(Recipient{}, "pt", NoMark), "Boas-vindas, Mundo")
+}
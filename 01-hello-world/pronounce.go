@@ -0,0 +1,44 @@
+package main
+
+import "learn-go/internal/pronounce"
+
+// pronunciations says how to pronounce the names speech synthesizers get
+// wrong, by the language of the name.
+var pronunciations = seedPronunciations([]pronounce.Entry{
+	{Name: "Elodie", Language: "fr", IPA: "e.lɔ.di"},
+})
+
+// seedPronunciations returns a dictionary of seeds. It panics on a seed
+// the dictionary doesn't accept, so a typo in one fails at startup.
+func seedPronunciations(seeds []pronounce.Entry) *pronounce.Dict {
+	d := pronounce.New()
+	for _, e := range seeds {
+		if err := d.Add(e.Name, e.Language, e.IPA); err != nil {
+			panic(err)
+		}
+	}
+	return d
+}
+
+// HelloPronounced greets name in language like Hello, and also returns
+// how to pronounce the name in IPA, or "" if the pronunciations
+// dictionary doesn't say.
+func HelloPronounced(name, language string) (greeting, ipa string) {
+	greeting = Hello(name, language)
+	ipa, _ = pronunciations.Lookup(name, languages[resolveLanguage(language)].Tag)
+	return greeting, ipa
+}
+
+// pronunciation is how to say r's name in a greeting in l: r.Phoneme if
+// it is set, or what the dictionary has for the name's language.
+func pronunciation(r Recipient, l Language) string {
+	if r.Phoneme != "" {
+		return r.Phoneme
+	}
+	tag := r.NameTag
+	if tag == "" {
+		tag = l.Tag
+	}
+	ipa, _ := pronunciations.Lookup(r.Name, tag)
+	return ipa
+}
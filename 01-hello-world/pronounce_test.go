@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"learn-go/internal/pronounce"
+)
+
+func TestHelloPronounced(t *testing.T) {
+	cases := []struct {
+		name, language string
+		greeting, ipa  string
+	}{
+		{"Elodie", "French", "Bonjour, Elodie", "e.lɔ.di"},
+		{"Elodie", "English", "Hello, Elodie", ""},
+		{"Max", "French", "Bonjour, Max", ""},
+		{"Elodie", "Klingon", "Hello, Elodie", ""},
+	}
+	for _, c := range cases {
+		greeting, ipa := HelloPronounced(c.name, c.language)
+		if greeting != c.greeting || ipa != c.ipa {
+			t.Errorf("HelloPronounced(%q, %q): got %q, %q want %q, %q", c.name, c.language, greeting, ipa, c.greeting, c.ipa)
+		}
+	}
+}
+
+func TestSpeakHelloPronunciations(t *testing.T) {
+	got := SpeakHello(Recipient{Name: "Elodie", NameTag: "fr"}, "English", Style{})
+	assertCorrectMessage(t, got, ssml+`"en"><s>Hello, <lang xml:lang="fr"><phoneme alphabet="ipa" ph="e.lɔ.di">Elodie</phoneme></lang></s></speak>`)
+
+	// The recipient's own phoneme wins.
+	got = SpeakHello(Recipient{Name: "Elodie", Phoneme: "ɛlodi"}, "French", Style{})
+	assertCorrectMessage(t, got, ssml+`"fr"><s>Bonjour, <phoneme alphabet="ipa" ph="ɛlodi">Elodie</phoneme></s></speak>`)
+}
+
+func TestSeedPronunciations(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("want a panic for a seed that isn't IPA")
+		}
+	}()
+	seedPronunciations([]pronounce.Entry{{Name: "Elodie", Language: "fr", IPA: "E.lO.di!"}})
+}
@@ -0,0 +1,58 @@
+package main
+
+// Mark is how a greeting ends.
+type Mark int
+
+const (
+	NoMark Mark = iota
+	Exclamation
+	Question
+)
+
+// Compose punctuates greeting and name as one sentence ending in m.
+func (p Punctuation) Compose(greeting, name string, m Mark) string {
+	return p.sentence(greeting+p.Comma, name, m).String()
+}
+
+// sentence is a composed greeting in parts, for renderers that mark the
+// name up. The prefix is the greeting and its comma, as the catalog has
+// them.
+type sentence struct {
+	open, prefix, name, end string
+}
+
+func (p Punctuation) sentence(prefix, name string, m Mark) sentence {
+	s := sentence{prefix: prefix, name: name}
+	switch m {
+	case Exclamation:
+		s.open, s.end = p.OpenExclamation, p.MarkSpace+p.Exclamation
+	case Question:
+		s.open, s.end = p.OpenQuestion, p.MarkSpace+p.Question
+	}
+	return s
+}
+
+func (s sentence) String() string {
+	return s.open + s.prefix + s.name + s.end
+}
+
+// Style is how to greet: how familiarly, with which mark at the end and,
+// when there's no name, whom. The zero Style is what Hello does.
+type Style struct {
+	Register Register
+	Mark     Mark
+	Audience Audience
+	// EnglishWorld greets "World" when there's no name, in every language
+	// and for every audience, as Hello did before addressees were
+	// translated, for callers that depend on that output.
+	EnglishWorld bool
+}
+
+// Greet greets name in language in style s, punctuated the way the
+// language does it, or in English if the language is unknown.
+func Greet(name, language string, s Style) string {
+	language = resolveLanguage(language)
+	greeting, fallback := greet(name, language, s)
+	recordGreeting(language, fallback)
+	return greeting
+}
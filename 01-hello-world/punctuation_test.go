@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+func TestGreet(t *testing.T) {
+	cases := []struct {
+		language string
+		style    Style
+		want     string
+	}{
+		{"English", Style{}, "Hello, Max"},
+		{"English", Style{Mark: Exclamation}, "Hello, Max!"},
+		{"English", Style{Register: Informal, Mark: Question}, "Hi, Max?"},
+		{"Spanish", Style{Mark: Exclamation}, "¡Hola, Max!"},
+		{"Spanish", Style{Register: Formal, Mark: Question}, "¿Saludos, Max?"},
+		{"French", Style{Mark: Exclamation}, "Bonjour, Max\u202f!"},
+		{"French", Style{Register: Informal, Mark: Question}, "Salut, Max\u202f?"},
+		{"Chinese", Style{Mark: Exclamation}, "你好，Max！"},
+		{"Japanese", Style{Mark: Question}, "こんにちは、Max？"},
+		{"Greek", Style{Mark: Question}, "Γεια σας, Max;"},
+		{"Arabic", Style{Mark: Question}, "مرحبا، Max؟"},
+		{"Amharic", Style{Mark: Exclamation}, "ሰላም፣ Max!"},
+		{"Thai", Style{Mark: Exclamation}, "สวัสดี Max!"},
+		{"Klingon", Style{Mark: Exclamation}, "Hello, Max!"},
+	}
+	for _, c := range cases {
+		t.Run(c.language, func(t *testing.T) {
+			assertCorrectMessage(t, Greet("Max", c.language, c.style), c.want)
+		})
+	}
+}
+
+func TestGreetMatchesHello(t *testing.T) {
+	for name := range languages {
+		assertCorrectMessage(t, Greet("Max", name, Style{}), Hello("Max", name))
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import "fmt"
+
+// Form is the grammatical form words addressed to someone agree with, in
+// languages where "welcome" changes with who is welcomed.
+type Form int
+
+const (
+	// Epicene words don't mark gender at all, like Spanish "Te damos la
+	// bienvenida". It is the zero Form: nothing about the recipient is
+	// assumed unless they said so.
+	Epicene Form = iota
+	// Inclusive forms mark that no gender is implied, like "Bienvenide".
+	Inclusive
+	Feminine
+	Masculine
+)
+
+var formNames = []string{"epicene", "inclusive", "feminine", "masculine"}
+
+func (f Form) String() string {
+	if f < 0 || int(f) >= len(formNames) {
+		return fmt.Sprintf("Form(%d)", int(f))
+	}
+	return formNames[f]
+}
+
+// ParseForm returns the Form named s, as stored in a recipient's
+// preferences.
+func ParseForm(s string) (Form, error) {
+	for i, name := range formNames {
+		if s == name {
+			return Form(i), nil
+		}
+	}
+	return Epicene, fmt.Errorf("unknown grammatical form %q", s)
+}
+
+// Recipient is who a greeting is for.
+type Recipient struct {
+	Name string
+	// Form is the form the recipient asked to be addressed in.
+	Form Form
+	// NameTag is the BCP 47 tag of the language the name is from, when
+	// it isn't the greeting's, so speech synthesis says it right.
+	NameTag string
+	// Phoneme is how to say the name in IPA, overriding the synthesizer's
+	// guess.
+	Phoneme string
+}
+
+// welcomes is "welcome" in the languages where it agrees with the
+// recipient. Every language here has an Epicene form, which is what the
+// others fall back to; checkWelcomes makes sure.
+var welcomes = map[string]map[Form]string{
+	"English": {Epicene: "Welcome"},
+	"German":  {Epicene: "Willkommen"},
+	"Spanish": {
+		Epicene:   "Te damos la bienvenida",
+		Inclusive: "Bienvenide",
+		Feminine:  "Bienvenida",
+		Masculine: "Bienvenido",
+	},
+	"French": {
+		Epicene:   "Bienvenue", // the noun, not the adjective
+		Inclusive: "Bienvenu·e",
+		Feminine:  "Bienvenue",
+		Masculine: "Bienvenu",
+	},
+	"Portuguese": {
+		Epicene:   "Boas-vindas",
+		Inclusive: "Bem-vinde",
+		Feminine:  "Bem-vinda",
+		Masculine: "Bem-vindo",
+	},
+	"Italian": {
+		Epicene:   "Ti diamo il benvenuto",
+		Inclusive: "Benvenutə",
+		Feminine:  "Benvenuta",
+		Masculine: "Benvenuto",
+	},
+	"Catalan": {
+		Epicene:   "Et donem la benvinguda",
+		Feminine:  "Benvinguda",
+		Masculine: "Benvingut",
+	},
+}
+
+// welcome returns "welcome" in language for r: the form r asked for, or
+// else the Epicene form. It reports false for a language without a
+// welcome, which HelloTo greets with its neutral greeting instead, as
+// that never marks gender either. Feminine and Masculine are never used
+// for someone who didn't ask for them, and never for each other.
+func welcome(language string, r Recipient) (string, bool) {
+	forms := welcomes[language]
+	if w, ok := forms[r.Form]; ok {
+		return w, true
+	}
+	w, ok := forms[Epicene]
+	return w, ok
+}
+
+// HelloTo welcomes r in language, in the grammatical form r asked for
+// or, failing that, one that assumes nothing about them.
+func HelloTo(r Recipient, language string, m Mark) string {
+	language = resolveLanguage(language)
+	greeting, fallback := welcomeTo(r, language, m)
+	recordGreeting(language, fallback)
+	return greeting
+}
+
+// welcomeTo builds HelloTo's greeting and reports whether language fell
+// back to English.
+func welcomeTo(r Recipient, language string, m Mark) (greeting string, fallback bool) {
+	l, prefix, known := lookupGreeting(language, Neutral)
+	if r.Name == "" {
+		r.Name = addressee(l.Name, Style{})
+	}
+	if w, ok := welcome(l.Name, r); ok {
+		return l.Compose(w, r.Name, m), !known
+	}
+	return l.sentence(prefix, r.Name, m).String(), !known
+}
+
+func init() {
+	if err := checkWelcomes(); err != nil {
+		panic(err)
+	}
+}
+
+// checkWelcomes reports languages in welcomes that Hello doesn't know or
+// that have no Epicene form to fall back to.
+func checkWelcomes() error {
+	for name, forms := range welcomes {
+		if _, ok := languages[name]; !ok {
+			return fmt.Errorf("welcomes: unknown language %s", name)
+		}
+		if forms[Epicene] == "" {
+			return fmt.Errorf("welcomes: %s has no epicene form", name)
+		}
+		for f, w := range forms {
+			if f < Epicene || f > Masculine || w == "" {
+				return fmt.Errorf("welcomes: %s: bad %v form %q", name, f, w)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestHelloTo(t *testing.T) {
+	cases := []struct {
+		name     string
+		language string
+		form     Form
+		want     string
+	}{
+		{"no preference", "Spanish", Epicene, "¡Te damos la bienvenida, Alex!"},
+		{"inclusive", "Spanish", Inclusive, "¡Bienvenide, Alex!"},
+		{"feminine", "Spanish", Feminine, "¡Bienvenida, Alex!"},
+		{"masculine", "Spanish", Masculine, "¡Bienvenido, Alex!"},
+		{"French inclusive", "French", Inclusive, "Bienvenu·e, Alex\u202f!"},
+		{"French no preference", "French", Epicene, "Bienvenue, Alex\u202f!"},
+		{"Catalan inclusive falls back to epicene", "Catalan", Inclusive, "Et donem la benvinguda, Alex!"},
+		{"English has one form", "English", Masculine, "Welcome, Alex!"},
+		{"no welcome falls back to the greeting", "Japanese", Feminine, "こんにちは、Alex！"},
+		{"unknown language", "Klingon", Inclusive, "Welcome, Alex!"},
+		{"unknown form", "Spanish", Form(9), "¡Te damos la bienvenida, Alex!"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := HelloTo(Recipient{Name: "Alex", Form: c.form}, c.language, Exclamation)
+			assertCorrectMessage(t, got, c.want)
+		})
+	}
+}
+
+func TestWelcomes(t *testing.T) {
+	saved := welcomes
+	t.Cleanup(func() { welcomes = saved })
+
+	cases := []struct {
+		name  string
+		extra map[string]map[Form]string
+	}{
+		{"unknown language", map[string]map[Form]string{"Klingon": {Epicene: "yIghoS"}}},
+		{"no epicene form", map[string]map[Form]string{"German": {Feminine: "Willkommen"}}},
+		{"unknown form", map[string]map[Form]string{"German": {Epicene: "Willkommen", Form(9): "Willkommen"}}},
+		{"empty form", map[string]map[Form]string{"German": {Epicene: ""}}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			welcomes = maps.Clone(saved)
+			maps.Copy(welcomes, c.extra)
+			if err := checkWelcomes(); err == nil {
+				t.Error("want an error")
+			}
+		})
+	}
+}
+
+func TestParseForm(t *testing.T) {
+	for _, f := range []Form{Epicene, Inclusive, Feminine, Masculine} {
+		if got, err := ParseForm(f.String()); got != f || err != nil {
+			t.Errorf("ParseForm(%q): got %v, %v", f, got, err)
+		}
+	}
+	if _, err := ParseForm("male"); err == nil {
+		t.Error(`ParseForm("male"): want an error`)
+	}
+	if got := Form(9).String(); got != "Form(9)" {
+		t.Errorf("got %q", got)
+	}
+}
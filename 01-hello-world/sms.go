@@ -0,0 +1,11 @@
+package main
+
+import "learn-go/internal/sms"
+
+// HelloSMS greets name in language like Hello, encoded to be sent by SMS:
+// in GSM-7 if it can be, in UCS-2 otherwise, and split into concatenated
+// parts if it is too long for one. It returns an error for a greeting
+// too long for 255 parts.
+func HelloSMS(name, language string, o sms.Options) (sms.Message, error) {
+	return sms.Encode(Hello(name, language), o)
+}
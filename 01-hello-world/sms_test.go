@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"learn-go/internal/sms"
+)
+
+func TestHelloSMS(t *testing.T) {
+	cases := []struct {
+		name, language string
+		o              sms.Options
+		want           string
+		encoding       sms.Encoding
+		parts          int
+	}{
+		{"Max", "English", sms.Options{}, "Hello, Max", sms.GSM7, 1},
+		{"Max", "Spanish", sms.Options{}, "Hola, Max", sms.GSM7, 1},
+		{"Zoë", "Portuguese", sms.Options{}, "Olá, Zoë", sms.UCS2, 1},
+		{"Zoë", "Portuguese", sms.Options{Transliterate: true}, "Ola, Zoe", sms.GSM7, 1},
+		{"Max", "Chinese", sms.Options{Transliterate: true}, "你好，Max", sms.UCS2, 1},
+		{strings.Repeat("ú", 70), "Spanish", sms.Options{}, "Hola, " + strings.Repeat("ú", 70), sms.UCS2, 2},
+		{strings.Repeat("ú", 70), "Spanish", sms.Options{Transliterate: true}, "Hola, " + strings.Repeat("u", 70), sms.GSM7, 1},
+	}
+	for _, c := range cases {
+		m, err := HelloSMS(c.name, c.language, c.o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertCorrectMessage(t, m.Text, c.want)
+		if m.Encoding != c.encoding || len(m.Parts) != c.parts {
+			t.Errorf("%q: got %d parts in %v, want %d in %v", m.Text, len(m.Parts), m.Encoding, c.parts, c.encoding)
+		}
+	}
+
+	if _, err := HelloSMS(strings.Repeat("a", 40_000), "English", sms.Options{}); err == nil {
+		t.Error("want an error for a greeting longer than 255 parts")
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// SpeakHello greets r in language as SSML for a speech synthesizer. The
+// name is spoken in its own language when r.NameTag is set, as r.Phoneme
+// or the pronunciations dictionary says when they know how, and numbers
+// in it are read as numbers.
+func SpeakHello(r Recipient, language string, s Style) string {
+	language = resolveLanguage(language)
+	l, sn, known := greetingSentence(r, language, s)
+	recordGreeting(language, !known)
+
+	name := sayNumbers(sn.name)
+	if ipa := pronunciation(r, l); ipa != "" {
+		name = `<phoneme alphabet="ipa" ph="` + html.EscapeString(ipa) + `">` + html.EscapeString(sn.name) + `</phoneme>`
+	}
+	if r.NameTag != "" && r.NameTag != l.Tag {
+		name = `<lang xml:lang="` + html.EscapeString(r.NameTag) + `">` + name + `</lang>`
+	}
+
+	var b strings.Builder
+	b.WriteString(`<speak version="1.1" xmlns="http://www.w3.org/2001/10/synthesis" xml:lang="` + l.Tag + `">`)
+	b.WriteString("<s>" + html.EscapeString(sn.open+sn.prefix) + name + html.EscapeString(sn.end) + "</s>")
+	b.WriteString("</speak>")
+	return b.String()
+}
+
+// sayNumbers escapes name and wraps each run of digits in it in a
+// <say-as>, so "Agent 47" is "agent forty-seven", not "four seven".
+func sayNumbers(name string) string {
+	var b strings.Builder
+	for len(name) > 0 {
+		i := strings.IndexFunc(name, unicode.IsDigit)
+		if i < 0 {
+			b.WriteString(html.EscapeString(name))
+			break
+		}
+		b.WriteString(html.EscapeString(name[:i]))
+		name = name[i:]
+		j := strings.IndexFunc(name, func(r rune) bool { return !unicode.IsDigit(r) })
+		if j < 0 {
+			j = len(name)
+		}
+		b.WriteString(`<say-as interpret-as="cardinal">` + name[:j] + `</say-as>`)
+		name = name[j:]
+	}
+	return b.String()
+}
+
+// HTMLHello greets r in language as an HTML paragraph that screen
+// readers announce when it appears, read in the right language and
+// direction. The name is marked up with its own language when r.NameTag
+// is set, and isn't machine-translated.
+func HTMLHello(r Recipient, language string, s Style) string {
+	language = resolveLanguage(language)
+	l, sn, known := greetingSentence(r, language, s)
+	recordGreeting(language, !known)
+
+	dir := "ltr"
+	if l.RightToLeft() {
+		dir = "rtl"
+	}
+	name := `<span translate="no"`
+	if r.NameTag != "" && r.NameTag != l.Tag {
+		// dir="auto" keeps a left-to-right name in right-to-left text,
+		// or the other way round, from reordering the punctuation.
+		name += ` lang="` + html.EscapeString(r.NameTag) + `" dir="auto"`
+	}
+	name += ">" + html.EscapeString(sn.name) + "</span>"
+
+	return `<p role="status" aria-live="polite" lang="` + l.Tag + `" dir="` + dir + `">` +
+		html.EscapeString(sn.open+sn.prefix) + name + html.EscapeString(sn.end) + "</p>"
+}
+
+// greetingSentence composes the greeting the renderers mark up, the way
+// Greet does, and reports whether the catalog knew language. It leaves
+// counting the greeting to its caller.
+func greetingSentence(r Recipient, language string, s Style) (Language, sentence, bool) {
+	l, prefix, known := lookupGreeting(language, s.Register)
+	if r.Name == "" {
+		r.Name = addressee(l.Name, s)
+	}
+	return l, l.sentence(prefix, r.Name, s.Mark), known
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+const ssml = `<speak version="1.1" xmlns="http://www.w3.org/2001/10/synthesis" xml:lang=`
+
+func TestSpeakHello(t *testing.T) {
+	cases := []struct {
+		name      string
+		recipient Recipient
+		language  string
+		style     Style
+		want      string
+	}{
+		{
+			"plain", Recipient{Name: "Max"}, "English", Style{},
+			ssml + `"en"><s>Hello, Max</s></speak>`,
+		},
+		{
+			"name in another language", Recipient{Name: "Yuki", NameTag: "ja"}, "Spanish", Style{Mark: Exclamation},
+			ssml + `"es"><s>¡Hola, <lang xml:lang="ja">Yuki</lang>!</s></speak>`,
+		},
+		{
+			"name in the greeting's language", Recipient{Name: "Lucía", NameTag: "es"}, "Spanish", Style{},
+			ssml + `"es"><s>Hola, Lucía</s></speak>`,
+		},
+		{
+			"phoneme", Recipient{Name: "Siobhán", NameTag: "ga", Phoneme: "ʃɪˈvɔːn"}, "English", Style{Register: Informal},
+			ssml + `"en"><s>Hi, <lang xml:lang="ga"><phoneme alphabet="ipa" ph="ʃɪˈvɔːn">Siobhán</phoneme></lang></s></speak>`,
+		},
+		{
+			"numbers", Recipient{Name: "Agent 47 & R2D2"}, "English", Style{},
+			ssml + `"en"><s>Hello, Agent <say-as interpret-as="cardinal">47</say-as> &amp; R<say-as interpret-as="cardinal">2</say-as>D<say-as interpret-as="cardinal">2</say-as></s></speak>`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := SpeakHello(c.recipient, c.language, c.style)
+			assertCorrectMessage(t, got, c.want)
+			assertWellFormed(t, got)
+		})
+	}
+}
+
+func TestHTMLHello(t *testing.T) {
+	cases := []struct {
+		name      string
+		recipient Recipient
+		language  string
+		want      string
+	}{
+		{
+			"plain", Recipient{Name: "<Max>"}, "French",
+			`<p role="status" aria-live="polite" lang="fr" dir="ltr">Bonjour, <span translate="no">&lt;Max&gt;</span>` + "\u202f!</p>",
+		},
+		{
+			"right to left", Recipient{Name: "Max", NameTag: "en"}, "Arabic",
+			`<p role="status" aria-live="polite" lang="ar" dir="rtl">مرحبا، <span translate="no" lang="en" dir="auto">Max</span>!</p>`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := HTMLHello(c.recipient, c.language, Style{Mark: Exclamation})
+			assertCorrectMessage(t, got, c.want)
+			assertWellFormed(t, got)
+		})
+	}
+}
+
+func assertWellFormed(t testing.TB, doc string) {
+	t.Helper()
+	d := xml.NewDecoder(strings.NewReader(doc))
+	for {
+		_, err := d.Token()
+		if err != nil {
+			if err != io.EOF {
+				t.Errorf("not well-formed: %v", err)
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"time"
+
+	"learn-go/internal/analytics"
+)
+
+// greetingStats counts every greeting per language and hour. The
+// languages that fall back to English tell us which one to add next.
+var greetingStats = analytics.New(analytics.DefaultHours)
+
+func recordGreeting(language string, fallback bool) {
+	greetingStats.Record(analytics.Event{Language: language, Fallback: fallback, Time: time.Now()})
+}
+
+// flushGreetingStats adds this run's counts to the file read by
+// `hello-stats report`.
+func flushGreetingStats() error {
+	path, err := analytics.DefaultPath()
+	if err != nil {
+		return err
+	}
+	return greetingStats.Flush(path)
+}
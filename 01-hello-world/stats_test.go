@@ -0,0 +1,60 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"learn-go/internal/analytics"
+	"learn-go/internal/braille"
+	"learn-go/internal/sms"
+)
+
+func TestGreetingStats(t *testing.T) {
+	saved := greetingStats
+	greetingStats = analytics.New(analytics.DefaultHours)
+	t.Cleanup(func() { greetingStats = saved })
+
+	Hello("Max", "English")
+	Hello("Elodie", "Spanish")
+	Hello("Worf", "Klingon")
+	Hello("Worf", "Klingon")
+
+	got := greetingStats.TopFallbacks(10)
+	want := []analytics.Count{{Language: "Klingon", N: 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestGreetingCountedOnce(t *testing.T) {
+	saved := greetingStats
+	t.Cleanup(func() { greetingStats = saved })
+
+	greetings := map[string]func(){
+		"Hello":         func() { Hello("Max", "Spanish") },
+		"CachedHello":   func() { CachedHello("Max", "Spanish") },
+		"CachedGreet":   func() { CachedGreet("Max", "Spanish", Style{Register: Formal}) },
+		"CachedHelloTo": func() { CachedHelloTo(Recipient{Name: "Max"}, "Spanish", NoMark) },
+		"Greet":         func() { Greet("Max", "Spanish", Style{Mark: Exclamation}) },
+		"HelloFormal":   func() { HelloFormal("Max", "Spanish") },
+		"HelloTo":       func() { HelloTo(Recipient{Name: "Max"}, "Spanish", NoMark) },
+		"SpeakHello":    func() { SpeakHello(Recipient{Name: "Max"}, "Spanish", Style{}) },
+		"HTMLHello":     func() { HTMLHello(Recipient{Name: "Max"}, "Spanish", Style{}) },
+		"HelloFit":      func() { HelloFit("Max", "Spanish", 20) },
+		"HelloSMS":      func() { HelloSMS("Max", "Spanish", sms.Options{}) },
+		"HelloBraille":  func() { HelloBraille("Max", "Spanish", braille.Grade1) },
+	}
+	for name, greet := range greetings {
+		t.Run(name, func(t *testing.T) {
+			greetingStats = analytics.New(analytics.DefaultHours)
+			greet()
+			n := 0
+			for _, r := range greetingStats.Hourly() {
+				n += r.Greetings
+			}
+			if n != 1 {
+				t.Errorf("counted %d greetings, want 1", n)
+			}
+		})
+	}
+}
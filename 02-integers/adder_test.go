@@ -14,6 +14,12 @@ func TestAdder(t *testing.T) {
 	}
 }
 
+func BenchmarkAdd(b *testing.B) {
+	for b.Loop() {
+		Add(2, 2)
+	}
+}
+
 // Functions that start with Example are useful for
 // examples outside code in documentation if you really
 // want to go the extra mile.
@@ -0,0 +1,59 @@
+// Package concurrency turns the README's concurrency sketches into
+// primitives with tests: ForEach fans work out to a fixed number of
+// goroutines, Pipeline chains stages with bounded buffers, Group runs
+// goroutines that fail together and Semaphore limits how many run at once.
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ForEach calls fn for every item, with at most workers calls running at
+// once, and returns the errors of all the calls that failed, joined in
+// item order. Once ctx is done no new calls start and ctx's error is
+// joined too.
+func ForEach[T any](ctx context.Context, items []T, workers int, fn func(ctx context.Context, i int, item T) error) error {
+	workers = max(1, min(workers, len(items)))
+
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, len(items))
+		next = make(chan int)
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each worker writes only the errors of its own items, so
+			// errs needs no lock.
+			for i := range next {
+				errs[i] = fn(ctx, i, items[i])
+			}
+		}()
+	}
+
+	started := 0
+feed:
+	for i := range items {
+		// select picks at random when both cases are ready, so check ctx
+		// first to stop as soon as it's done.
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case next <- i:
+			started++
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	if started < len(items) {
+		errs = append(errs, ctx.Err())
+	}
+	return errors.Join(errs...)
+}
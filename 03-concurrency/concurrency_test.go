@@ -0,0 +1,256 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"learn-go/internal/assert"
+)
+
+// peak tracks how many calls run at once.
+type peak struct {
+	running, max atomic.Int32
+}
+
+func (p *peak) enter() {
+	n := p.running.Add(1)
+	for {
+		m := p.max.Load()
+		if n <= m || p.max.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+}
+
+func (p *peak) exit() { p.running.Add(-1) }
+
+func TestForEach(t *testing.T) {
+	errOdd := errors.New("odd")
+	cases := []struct {
+		name     string
+		items    []int
+		workers  int
+		wantErrs int
+	}{
+		{"no items", nil, 4, 0},
+		{"one worker", []int{2, 4, 6}, 1, 0},
+		{"more workers than items", []int{2, 4}, 10, 0},
+		{"every error", []int{1, 2, 3, 5}, 2, 3},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var p peak
+			var calls atomic.Int32
+			err := ForEach(context.Background(), c.items, c.workers, func(ctx context.Context, i int, n int) error {
+				p.enter()
+				defer p.exit()
+				calls.Add(1)
+				if n%2 == 1 {
+					return fmt.Errorf("item %d: %w", i, errOdd)
+				}
+				return nil
+			})
+			assert.Equal(t, int(calls.Load()), len(c.items))
+			assert.Equal(t, p.max.Load() <= int32(c.workers), true)
+			if c.wantErrs == 0 {
+				assert.Equal(t, err, nil)
+				return
+			}
+			assert.Equal(t, errors.Is(err, errOdd), true)
+			assert.Equal(t, err.Error(), "item 0: odd\nitem 2: odd\nitem 3: odd")
+		})
+	}
+
+	t.Run("stops when canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var calls atomic.Int32
+		err := ForEach(ctx, make([]int, 100), 2, func(ctx context.Context, i int, n int) error {
+			if calls.Add(1) == 3 {
+				cancel()
+			}
+			return nil
+		})
+		assert.Equal(t, errors.Is(err, context.Canceled), true)
+		assert.Equal(t, calls.Load() < 100, true)
+	})
+}
+
+func TestPipeline(t *testing.T) {
+	double := Stage[int]{Name: "double", Workers: 3, Fn: func(ctx context.Context, n int) (int, error) { return 2 * n, nil }}
+	inc := Stage[int]{Name: "inc", Workers: 2, Fn: func(ctx context.Context, n int) (int, error) { return n + 1, nil }}
+	failOn := func(bad int) Stage[int] {
+		return Stage[int]{Name: "check", Fn: func(ctx context.Context, n int) (int, error) {
+			if n == bad {
+				return 0, fmt.Errorf("%d is bad", n)
+			}
+			return n, nil
+		}}
+	}
+
+	cases := []struct {
+		name    string
+		stages  []Stage[int]
+		want    string
+		wantErr string
+	}{
+		{"no stages", nil, "[1 2 3 4 5]", ""},
+		{"in order", []Stage[int]{double, inc}, "[3 5 7 9 11]", ""},
+		{"order matters", []Stage[int]{inc, double}, "[4 6 8 10 12]", ""},
+		{"error stops it", []Stage[int]{double, failOn(6), inc}, "[]", "check: 6 is bad"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := Pipeline(context.Background(), []int{1, 2, 3, 4, 5}, 1, c.stages...)
+			assert.Equal(t, fmt.Sprint(got), c.want)
+			if c.wantErr != "" || err != nil {
+				assert.Equal(t, fmt.Sprint(err), c.wantErr)
+			}
+		})
+	}
+
+	t.Run("bounded", func(t *testing.T) {
+		// With a slow last stage, the fast first one can only get as far
+		// ahead as the buffers between them allow.
+		var produced, consumed atomic.Int32
+		var ahead atomic.Int32
+		fast := Stage[int]{Fn: func(ctx context.Context, n int) (int, error) {
+			produced.Add(1)
+			return n, nil
+		}}
+		slow := Stage[int]{Fn: func(ctx context.Context, n int) (int, error) {
+			if d := produced.Load() - consumed.Load(); d > ahead.Load() {
+				ahead.Store(d)
+			}
+			time.Sleep(time.Millisecond)
+			consumed.Add(1)
+			return n, nil
+		}}
+		if _, err := Pipeline(context.Background(), make([]int, 50), 2, fast, slow); err != nil {
+			t.Fatal(err)
+		}
+		// Two buffers, the value being sent and the one being processed.
+		assert.Equal(t, ahead.Load() <= 2+2+1+1, true)
+	})
+}
+
+func TestGroup(t *testing.T) {
+	t.Run("limit", func(t *testing.T) {
+		var g Group
+		g.SetLimit(3)
+		var p peak
+		for range 20 {
+			g.Go(func() error {
+				p.enter()
+				defer p.exit()
+				return nil
+			})
+		}
+		assert.Equal(t, g.Wait(), nil)
+		assert.Equal(t, p.max.Load() <= 3, true)
+	})
+
+	t.Run("negative limit is no limit", func(t *testing.T) {
+		var g Group
+		g.SetLimit(1)
+		g.SetLimit(-1)
+		const n = 20
+		var running atomic.Int32
+		release := make(chan struct{})
+		for range n {
+			g.Go(func() error {
+				running.Add(1)
+				<-release
+				return nil
+			})
+		}
+		for deadline := time.Now().Add(time.Second); running.Load() < n && time.Now().Before(deadline); {
+			time.Sleep(time.Millisecond)
+		}
+		assert.Equal(t, running.Load(), int32(n))
+		close(release)
+		assert.Equal(t, g.Wait(), nil)
+	})
+
+	t.Run("limit of 0 while running panics", func(t *testing.T) {
+		var g Group
+		g.SetLimit(1)
+		release := make(chan struct{})
+		g.Go(func() error {
+			<-release
+			return nil
+		})
+		defer func() {
+			r := recover()
+			close(release)
+			g.Wait()
+			if r == nil || !strings.Contains(fmt.Sprint(r), "1 goroutines") {
+				t.Errorf("got panic %v", r)
+			}
+		}()
+		g.SetLimit(0)
+	})
+
+	t.Run("first error cancels", func(t *testing.T) {
+		g, ctx := WithContext(context.Background())
+		errFirst := errors.New("first")
+		g.Go(func() error { return errFirst })
+		g.Go(func() error {
+			<-ctx.Done()
+			return errors.New("second")
+		})
+		assert.Equal(t, g.Wait(), errFirst)
+		assert.Equal(t, context.Cause(ctx), errFirst)
+	})
+}
+
+func TestSemaphore(t *testing.T) {
+	s := NewSemaphore(2)
+	assert.Equal(t, s.TryAcquire(), true)
+	assert.Equal(t, s.Acquire(context.Background()), nil)
+	assert.Equal(t, s.TryAcquire(), false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	assert.Equal(t, s.Acquire(ctx), context.DeadlineExceeded)
+
+	s.Release()
+	assert.Equal(t, s.TryAcquire(), true)
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "Release without Acquire") {
+			t.Errorf("got panic %v", r)
+		}
+	}()
+	s.Release()
+	s.Release()
+	s.Release()
+}
+
+func ExampleForEach() {
+	words := []string{"gopher", "channel", "select"}
+	lengths := make([]int, len(words))
+	err := ForEach(context.Background(), words, 2, func(ctx context.Context, i int, w string) error {
+		lengths[i] = len(w)
+		return nil
+	})
+	fmt.Println(lengths, err)
+	// Output: [6 7 6] <nil>
+}
+
+func ExamplePipeline() {
+	upper := Stage[string]{Name: "upper", Workers: 2, Fn: func(ctx context.Context, s string) (string, error) {
+		return strings.ToUpper(s), nil
+	}}
+	shout := Stage[string]{Name: "shout", Fn: func(ctx context.Context, s string) (string, error) {
+		return s + "!", nil
+	}}
+	out, err := Pipeline(context.Background(), []string{"hello", "gopher"}, 1, upper, shout)
+	fmt.Println(out, err)
+	// Output: [HELLO! GOPHER!] <nil>
+}
@@ -0,0 +1,79 @@
+package concurrency
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Group runs goroutines for one task and collects the first error, like
+// golang.org/x/sync/errgroup. The zero Group runs any number of
+// goroutines and cancels nothing.
+type Group struct {
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+	sem    *Semaphore
+	active atomic.Int64
+
+	once sync.Once
+	err  error
+}
+
+// WithContext returns a Group and a context that is canceled when a
+// goroutine of the group fails or Wait returns.
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// SetLimit lets at most n goroutines of the group run at once. As with
+// errgroup, a negative n means no limit and a limit of 0 keeps Go from
+// starting any. It must not be called concurrently with Go, and panics if
+// goroutines of the group are still running.
+func (g *Group) SetLimit(n int) {
+	if active := g.active.Load(); active != 0 {
+		panic(fmt.Sprintf("concurrency: SetLimit while %d goroutines of the group are running", active))
+	}
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	// Not NewSemaphore, which rounds 0 up to one slot.
+	g.sem = &Semaphore{slots: make(chan struct{}, n)}
+}
+
+// Go runs fn in a new goroutine. With a limit set, it waits for one of
+// the running goroutines to finish first.
+func (g *Group) Go(fn func() error) {
+	if g.sem != nil {
+		g.sem.Acquire(context.Background())
+	}
+	g.wg.Add(1)
+	g.active.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer g.active.Add(-1)
+		if g.sem != nil {
+			defer g.sem.Release()
+		}
+		if err := fn(); err != nil {
+			g.once.Do(func() {
+				g.err = err
+				if g.cancel != nil {
+					g.cancel(err)
+				}
+			})
+		}
+	}()
+}
+
+// Wait waits for every goroutine started with Go and returns the first
+// error one of them returned.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(g.err)
+	}
+	return g.err
+}
@@ -0,0 +1,89 @@
+package concurrency
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Stage is one step of a Pipeline.
+type Stage[T any] struct {
+	Name    string // used in errors
+	Workers int    // goroutines running Fn, at least 1
+	Fn      func(ctx context.Context, v T) (T, error)
+}
+
+type indexed[T any] struct {
+	i int
+	v T
+}
+
+// Pipeline passes items through stages in order and returns the results
+// in the order of items. Each stage runs its own workers, and the
+// channels between stages hold at most buffer values, so a slow stage
+// holds back the ones before it instead of piling up work. The first
+// error stops the pipeline.
+func Pipeline[T any](ctx context.Context, items []T, buffer int, stages ...Stage[T]) ([]T, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	src := make(chan indexed[T], buffer)
+	go func() {
+		defer close(src)
+		for i, v := range items {
+			select {
+			case src <- indexed[T]{i, v}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var in <-chan indexed[T] = src
+	for _, stage := range stages {
+		in = run(ctx, cancel, stage, in, buffer)
+	}
+
+	results := make([]T, len(items))
+	for r := range in {
+		results[r.i] = r.v
+	}
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// run starts stage's workers reading from in and returns their output.
+func run[T any](ctx context.Context, cancel context.CancelCauseFunc, stage Stage[T], in <-chan indexed[T], buffer int) <-chan indexed[T] {
+	out := make(chan indexed[T], buffer)
+	var wg sync.WaitGroup
+	for range max(stage.Workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range in {
+				if ctx.Err() != nil {
+					continue // drain in so the stage before can finish
+				}
+				v, err := stage.Fn(ctx, r.v)
+				if err != nil {
+					if stage.Name != "" {
+						err = fmt.Errorf("%s: %w", stage.Name, err)
+					}
+					cancel(err)
+					continue
+				}
+				select {
+				case out <- indexed[T]{r.i, v}:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
@@ -0,0 +1,49 @@
+package concurrency
+
+import "context"
+
+// Semaphore lets at most a set number of goroutines hold it at once.
+type Semaphore struct {
+	slots chan struct{}
+}
+
+// NewSemaphore returns a Semaphore with n slots.
+func NewSemaphore(n int) *Semaphore {
+	return &Semaphore{slots: make(chan struct{}, max(n, 1))}
+}
+
+// Acquire waits for a free slot, or for ctx to be done.
+func (s *Semaphore) Acquire(ctx context.Context) error {
+	// Prefer a free slot over a context that is already done, so a
+	// semaphore with room never fails.
+	select {
+	case s.slots <- struct{}{}:
+		return nil
+	default:
+	}
+	select {
+	case s.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TryAcquire takes a slot if one is free and reports whether it did.
+func (s *Semaphore) TryAcquire() bool {
+	select {
+	case s.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Release frees a slot taken by Acquire or TryAcquire.
+func (s *Semaphore) Release() {
+	select {
+	case <-s.slots:
+	default:
+		panic("concurrency: Release without Acquire")
+	}
+}
@@ -0,0 +1,232 @@
+// Package server is the README's functional options pattern grown into a
+// real greeting server: New takes any number of Options, checks that they
+// make sense together and builds the handler chain from them.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Logger is all the server needs to log, so any *log.Logger will do.
+type Logger interface {
+	Printf(format string, args ...any)
+}
+
+// Catalog knows the greeting prefix of each language.
+type Catalog interface {
+	// Prefix returns the prefix for language, such as "Hola, ", and
+	// whether the language is known.
+	Prefix(language string) (string, bool)
+}
+
+// MapCatalog is a Catalog of language names to prefixes.
+type MapCatalog map[string]string
+
+func (c MapCatalog) Prefix(language string) (string, bool) {
+	p, ok := c[language]
+	return p, ok
+}
+
+// DefaultCatalog speaks the languages of 01-hello-world.
+var DefaultCatalog = MapCatalog{
+	"English": "Hello, ",
+	"Spanish": "Hola, ",
+	"French":  "Bonjour, ",
+}
+
+// Timeouts bound how long the server spends on a connection. A zero
+// duration means no limit.
+type Timeouts struct {
+	Read  time.Duration // reading a whole request
+	Write time.Duration // from the end of the request to the end of the response
+	Idle  time.Duration // keep-alive connections between requests
+	// Handler is how long a handler may run before the client gets a
+	// 503. It must be shorter than Write, or the 503 can't be written.
+	Handler time.Duration
+}
+
+// DefaultTimeouts are used unless WithTimeouts says otherwise.
+var DefaultTimeouts = Timeouts{Read: 5 * time.Second, Write: 10 * time.Second, Idle: time.Minute, Handler: 5 * time.Second}
+
+// Middleware wraps a handler, to log, authenticate, add headers...
+type Middleware func(http.Handler) http.Handler
+
+// Server serves greetings over HTTP.
+type Server struct {
+	addr       string
+	logger     Logger
+	catalog    Catalog
+	timeouts   Timeouts
+	middleware []Middleware
+
+	given map[string]bool // options applied, to catch repeats
+	errs  []error
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithAddr sets the address to listen on, ":8080" by default.
+func WithAddr(addr string) Option {
+	return func(s *Server) {
+		s.once("WithAddr")
+		if _, port, err := net.SplitHostPort(addr); err != nil {
+			s.fail(fmt.Errorf("WithAddr(%q): %w", addr, err))
+		} else if _, err := strconv.ParseUint(port, 10, 16); err != nil && port != "" {
+			s.fail(fmt.Errorf("WithAddr(%q): bad port", addr))
+		}
+		s.addr = addr
+	}
+}
+
+// WithLogger sets where requests and errors are logged, the standard
+// logger by default.
+func WithLogger(l Logger) Option {
+	return func(s *Server) {
+		s.once("WithLogger")
+		if l == nil {
+			s.fail(errors.New("WithLogger(nil)"))
+		}
+		s.logger = l
+	}
+}
+
+// WithCatalog sets the languages the server greets in, DefaultCatalog by
+// default.
+func WithCatalog(c Catalog) Option {
+	return func(s *Server) {
+		s.once("WithCatalog")
+		if c == nil {
+			s.fail(errors.New("WithCatalog(nil)"))
+		}
+		s.catalog = c
+	}
+}
+
+// WithTimeouts replaces DefaultTimeouts.
+func WithTimeouts(t Timeouts) Option {
+	return func(s *Server) {
+		s.once("WithTimeouts")
+		if t.Read < 0 || t.Write < 0 || t.Idle < 0 || t.Handler < 0 {
+			s.fail(fmt.Errorf("WithTimeouts: negative timeout in %+v", t))
+		}
+		s.timeouts = t
+	}
+}
+
+// WithMiddleware adds middleware around the server's handlers. It can be
+// given more than once; the first middleware given sees requests first.
+func WithMiddleware(m ...Middleware) Option {
+	return func(s *Server) {
+		for _, mw := range m {
+			if mw == nil {
+				s.fail(errors.New("WithMiddleware(nil)"))
+				continue
+			}
+			s.middleware = append(s.middleware, mw)
+		}
+	}
+}
+
+func (s *Server) once(option string) {
+	if s.given[option] {
+		s.fail(fmt.Errorf("%s given twice", option))
+	}
+	s.given[option] = true
+}
+
+func (s *Server) fail(err error) { s.errs = append(s.errs, err) }
+
+// New returns a Server configured by opts. It fails if an option is
+// invalid, given twice, or conflicts with another.
+func New(opts ...Option) (*Server, error) {
+	s := &Server{
+		addr:     ":8080",
+		logger:   log.Default(),
+		catalog:  DefaultCatalog,
+		timeouts: DefaultTimeouts,
+		given:    map[string]bool{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if t := s.timeouts; t.Handler > 0 && t.Write > 0 && t.Handler >= t.Write {
+		s.fail(fmt.Errorf("handler timeout %v must be shorter than write timeout %v", t.Handler, t.Write))
+	}
+	if err := errors.Join(s.errs...); err != nil {
+		return nil, fmt.Errorf("server: %w", err)
+	}
+	return s, nil
+}
+
+// Addr is the address the server listens on.
+func (s *Server) Addr() string { return s.addr }
+
+// Handler returns the server's routes wrapped in its middleware: logging
+// first, then the middleware given in order, then the handler timeout.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /hello", Hello(s.catalog))
+
+	var h http.Handler = mux
+	if s.timeouts.Handler > 0 {
+		h = http.TimeoutHandler(h, s.timeouts.Handler, "greeting took too long\n")
+	}
+	for i := len(s.middleware) - 1; i >= 0; i-- {
+		h = s.middleware[i](h)
+	}
+	return logRequests(s.logger, h)
+}
+
+// Run serves until ctx is done, then waits for requests in flight to
+// finish.
+func (s *Server) Run(ctx context.Context) error {
+	l, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, l)
+}
+
+// Serve is Run on a listener the caller opened.
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	srv := &http.Server{
+		Handler:      s.Handler(),
+		ReadTimeout:  s.timeouts.Read,
+		WriteTimeout: s.timeouts.Write,
+		IdleTimeout:  s.timeouts.Idle,
+		ErrorLog:     log.New(logWriter{s.logger}, "", 0),
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(l) }()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	shutdown, cancel := context.WithTimeout(context.Background(), s.timeouts.Write+time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdown); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// logWriter sends the http.Server's own errors to a Logger.
+type logWriter struct{ l Logger }
+
+func (w logWriter) Write(p []byte) (int, error) {
+	w.l.Printf("%s", p)
+	return len(p), nil
+}
@@ -0,0 +1,236 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"learn-go/internal/assert"
+)
+
+// get sends a GET for target to h and returns the status and body.
+func get(t testing.TB, h http.Handler, target string) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec.Code, rec.Body.String()
+}
+
+func mustNew(t testing.TB, opts ...Option) *Server {
+	t.Helper()
+	s, err := New(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// quiet keeps request logs out of the test output.
+var quiet = WithLogger(&lines{})
+
+type lines struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *lines) Printf(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func TestHello(t *testing.T) {
+	cases := []struct {
+		name   string
+		target string
+		want   string
+	}{
+		{"name and language", "/hello?name=Elodie&lang=Spanish", "Hola, Elodie\n"},
+		{"French", "/hello?name=James&lang=French", "Bonjour, James\n"},
+		{"no name", "/hello?lang=Spanish", "Hola, World\n"},
+		{"unknown language", "/hello?name=Max&lang=Klingon", "Hello, Max\n"},
+		{"nothing", "/hello", "Hello, World\n"},
+	}
+	h := mustNew(t, quiet).Handler()
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			status, body := get(t, h, c.target)
+			assert.Equal(t, status, http.StatusOK)
+			assert.Equal(t, body, c.want)
+		})
+	}
+
+	t.Run("wrong method", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/hello", nil))
+		assert.Equal(t, rec.Code, http.StatusMethodNotAllowed)
+	})
+}
+
+func TestNew(t *testing.T) {
+	pass := func(next http.Handler) http.Handler { return next }
+	cases := []struct {
+		name    string
+		opts    []Option
+		wantErr string
+	}{
+		{"defaults", nil, ""},
+		{"every option", []Option{WithAddr("localhost:9090"), quiet, WithCatalog(DefaultCatalog), WithTimeouts(DefaultTimeouts), WithMiddleware(pass), WithMiddleware(pass)}, ""},
+		{"no timeouts", []Option{WithTimeouts(Timeouts{})}, ""},
+		{"bad address", []Option{WithAddr("8080")}, `WithAddr("8080"): address 8080: missing port in address`},
+		{"bad port", []Option{WithAddr(":http8")}, `WithAddr(":http8"): bad port`},
+		{"address twice", []Option{WithAddr(":1"), WithAddr(":2")}, "WithAddr given twice"},
+		{"nil logger", []Option{WithLogger(nil)}, "WithLogger(nil)"},
+		{"catalog twice", []Option{WithCatalog(DefaultCatalog), WithCatalog(MapCatalog{})}, "WithCatalog given twice"},
+		{"nil catalog", []Option{WithCatalog(nil)}, "WithCatalog(nil)"},
+		{"nil middleware", []Option{WithMiddleware(pass, nil)}, "WithMiddleware(nil)"},
+		{"negative timeout", []Option{WithTimeouts(Timeouts{Idle: -1})}, "WithTimeouts: negative timeout in {Read:0s Write:0s Idle:-1ns Handler:0s}"},
+		{"handler outlives write", []Option{WithTimeouts(Timeouts{Write: time.Second, Handler: time.Second})}, "handler timeout 1s must be shorter than write timeout 1s"},
+		{"every error", []Option{WithAddr(":1"), WithAddr(":2"), WithLogger(nil)}, "WithAddr given twice\nWithLogger(nil)"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := New(c.opts...)
+			if c.wantErr == "" {
+				assert.Equal(t, err, nil)
+				return
+			}
+			assert.Equal(t, fmt.Sprint(err), "server: "+c.wantErr)
+		})
+	}
+}
+
+func TestWithCatalog(t *testing.T) {
+	s := mustNew(t, quiet, WithCatalog(MapCatalog{"English": "Hi, ", "Klingon": "nuqneH, "}))
+	_, body := get(t, s.Handler(), "/hello?name=Worf&lang=Klingon")
+	assert.Equal(t, body, "nuqneH, Worf\n")
+	_, body = get(t, s.Handler(), "/hello?name=Max&lang=Spanish")
+	assert.Equal(t, body, "Hi, Max\n")
+}
+
+func TestWithLogger(t *testing.T) {
+	l := &lines{}
+	s := mustNew(t, WithLogger(l))
+	get(t, s.Handler(), "/hello?name=Max")
+	get(t, s.Handler(), "/nowhere")
+
+	assert.Equal(t, len(l.lines), 2)
+	assert.Equal(t, strings.HasPrefix(l.lines[0], "GET /hello?name=Max 200 "), true)
+	assert.Equal(t, strings.HasPrefix(l.lines[1], "GET /nowhere 404 "), true)
+}
+
+func TestWithMiddleware(t *testing.T) {
+	tag := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("X-Seen-By", name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	deny := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("name") == "Mallory" {
+				http.Error(w, "go away", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+	s := mustNew(t, quiet, WithMiddleware(tag("first"), tag("second")), WithMiddleware(deny))
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/hello?name=Max", nil))
+	assert.Equal(t, strings.Join(rec.Header().Values("X-Seen-By"), ","), "first,second")
+	assert.Equal(t, rec.Body.String(), "Hello, Max\n")
+
+	status, _ := get(t, s.Handler(), "/hello?name=Mallory")
+	assert.Equal(t, status, http.StatusForbidden)
+}
+
+// slowCatalog takes a while to look up each prefix.
+type slowCatalog time.Duration
+
+func (c slowCatalog) Prefix(language string) (string, bool) {
+	time.Sleep(time.Duration(c))
+	return DefaultCatalog.Prefix(language)
+}
+
+func TestWithTimeouts(t *testing.T) {
+	s := mustNew(t, quiet, WithCatalog(slowCatalog(50*time.Millisecond)), WithTimeouts(Timeouts{Write: time.Second, Handler: 10 * time.Millisecond}))
+	status, body := get(t, s.Handler(), "/hello?name=Max")
+	assert.Equal(t, status, http.StatusServiceUnavailable)
+	assert.Equal(t, body, "greeting took too long\n")
+
+	s = mustNew(t, quiet, WithCatalog(slowCatalog(time.Millisecond)), WithTimeouts(Timeouts{}))
+	status, _ = get(t, s.Handler(), "/hello?name=Max")
+	assert.Equal(t, status, http.StatusOK)
+}
+
+func TestWithAddr(t *testing.T) {
+	// Find a free port, then let the server listen on it.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	s := mustNew(t, quiet, WithAddr(addr))
+	assert.Equal(t, s.Addr(), addr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+
+	var body []byte
+	for range 50 {
+		resp, err := http.Get("http://" + addr + "/hello?name=Max&lang=French")
+		if err != nil {
+			time.Sleep(10 * time.Millisecond)
+			continue
+		}
+		body, _ = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		break
+	}
+	assert.Equal(t, string(body), "Bonjour, Max\n")
+
+	cancel()
+	assert.Equal(t, <-done, nil)
+}
+
+func ExampleNew() {
+	s, err := New(
+		WithAddr(":8081"),
+		WithCatalog(MapCatalog{"English": "Hello, ", "Italian": "Ciao, "}),
+		WithMiddleware(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Greeter", "learn-go")
+				next.ServeHTTP(w, r)
+			})
+		}),
+		WithLogger(&lines{}),
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/hello?name=Gopher&lang=Italian", nil))
+	fmt.Print(rec.Header().Get("X-Greeter"), " ", rec.Body)
+	// Output: learn-go Ciao, Gopher
+}
+
+func ExampleNew_conflict() {
+	_, err := New(WithTimeouts(Timeouts{Write: time.Second, Handler: 2 * time.Second}))
+	fmt.Println(err)
+	// Output: server: handler timeout 2s must be shorter than write timeout 1s
+}
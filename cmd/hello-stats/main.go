@@ -0,0 +1,50 @@
+// Command hello-stats reports the greeting analytics collected by Hello.
+//
+//	hello-stats report [-file path] [-view hourly|fallbacks] [-format table|csv] [-top n]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"learn-go/internal/analytics"
+)
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != "report" {
+		fmt.Fprintln(os.Stderr, "usage: hello-stats report [flags]")
+		os.Exit(2)
+	}
+	if err := report(os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "hello-stats:", err)
+		os.Exit(1)
+	}
+}
+
+func report(args []string) error {
+	path, err := analytics.DefaultPath()
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	fs.StringVar(&path, "file", path, "stats file written by hello")
+	view := fs.String("view", "hourly", "what to show: hourly or fallbacks")
+	format := fs.String("format", "table", "output format: table or csv")
+	top := fs.Int("top", 10, "number of languages in the fallbacks view")
+	fs.Parse(args)
+
+	a := analytics.New(analytics.DefaultHours)
+	if err := a.Load(path); err != nil {
+		return err
+	}
+
+	switch *view {
+	case "hourly":
+		return a.WriteHourly(os.Stdout, analytics.Format(*format))
+	case "fallbacks":
+		return a.WriteTopFallbacks(os.Stdout, analytics.Format(*format), *top)
+	}
+	return fmt.Errorf("unknown view %q", *view)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"time"
+
+	"learn-go/internal/bench"
+)
+
+func runBench(root string, args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	pattern := fs.String("bench", ".", "run only the benchmarks matching this regexp")
+	count := fs.Int("count", 10, "runs of each benchmark")
+	benchtime := fs.String("benchtime", "", "time per run, as for go test")
+	against := fs.String("against", "", "revision to compare with (default the last other one benchmarked)")
+	fs.Parse(args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	rev, err := bench.Revision(ctx, root)
+	if err != nil {
+		return err
+	}
+	history, err := bench.LoadHistory(root)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Running benchmarks %d times at %s...\n", *count, rev)
+	results, err := bench.Run(ctx, root, bench.Options{Packages: fs.Args(), Bench: *pattern, Count: *count, Benchtime: *benchtime})
+	if err != nil {
+		return err
+	}
+	record := &bench.Record{Rev: rev, Time: time.Now(), GoVersion: runtime.Version(), Benchmarks: results}
+	history.Add(record)
+	if err := history.Save(root); err != nil {
+		return err
+	}
+
+	base, ok := history.Records[*against]
+	if *against == "" {
+		base, ok = history.Baseline(rev)
+	}
+	if !ok {
+		if *against != "" {
+			return fmt.Errorf("no benchmarks recorded for %s", *against)
+		}
+		fmt.Println("Saved. Benchmark another revision to compare with this one.")
+		return nil
+	}
+
+	deltas := bench.Compare(base.Benchmarks, record.Benchmarks)
+	fmt.Println()
+	if err := bench.WriteComparison(os.Stdout, base.Rev, rev, deltas); err != nil {
+		return err
+	}
+	regressions := 0
+	for _, d := range deltas {
+		if d.Regression() {
+			regressions++
+		}
+	}
+	if regressions > 0 {
+		return fmt.Errorf("%d significant regressions since %s", regressions, base.Rev)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+
+	"learn-go/internal/chapters"
+	"learn-go/internal/coverage"
+)
+
+func showCoverage(root string, args []string) error {
+	fs := flag.NewFlagSet("coverage", flag.ExitOnError)
+	html := fs.String("html", ".learn/coverage.html", "where to write the HTML report, relative to the module root")
+	profile := fs.String("profile", ".learn/coverage.out", "where to write the merged profile, for `go tool cover`")
+	fs.Parse(args)
+
+	all, err := chapters.Discover(root)
+	if err != nil {
+		return err
+	}
+	if names := fs.Args(); len(names) > 0 {
+		all = slices.DeleteFunc(all, func(ch chapters.Chapter) bool { return !slices.Contains(names, ch.Name) })
+		if len(all) == 0 {
+			return fmt.Errorf("no chapter called %q", names)
+		}
+	}
+	cfg, err := coverage.LoadConfig(root)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var (
+		reports  []*coverage.Report
+		profiles []*coverage.Profile
+		below    int
+	)
+	for _, ch := range all {
+		r, err := coverage.Run(ctx, root, cfg, ch)
+		if err != nil {
+			return err
+		}
+		reports = append(reports, r)
+		profiles = append(profiles, r.Profile)
+		if !r.OK() {
+			below++
+		}
+	}
+	if err := coverage.WriteText(os.Stdout, reports); err != nil {
+		return err
+	}
+
+	merged, err := coverage.Merge(profiles...)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(root, *profile), merged.Write); err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(root, *html), func(w io.Writer) error { return coverage.WriteHTML(w, reports) }); err != nil {
+		return err
+	}
+	fmt.Printf("\nSee %s for the highlighted source.\n", *html)
+
+	if below > 0 {
+		return fmt.Errorf("%d of %d chapters fail their tests or fall below their minimum in %s", below, len(reports), coverage.ConfigFile)
+	}
+	return nil
+}
+
+func writeFile(path string, write func(io.Writer) error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
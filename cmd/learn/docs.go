@@ -0,0 +1,18 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+
+	"learn-go/internal/docsite"
+)
+
+func serveDocs(root string, args []string) error {
+	fs := flag.NewFlagSet("docs", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:6060", "address to serve the docs on")
+	fs.Parse(args)
+
+	fmt.Printf("Serving the chapters on http://%s/\n", *addr)
+	return http.ListenAndServe(*addr, docsite.Handler(root))
+}
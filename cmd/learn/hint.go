@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"learn-go/internal/chapters"
+	"learn-go/internal/hints"
+)
+
+func showHint(root string, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var (
+		r   chapters.Result
+		ok  bool
+		err error
+	)
+	if len(args) == 0 {
+		r, ok, err = nextFailing(ctx, root)
+	} else {
+		r, ok, err = runChapter(ctx, root, args[0])
+	}
+	if err != nil || !ok {
+		return err
+	}
+
+	state, err := hints.LoadState(root)
+	if err != nil {
+		return err
+	}
+	if r.Passed {
+		state.Reset(r.Chapter.Name)
+		return state.Save(root)
+	}
+
+	mistakes, err := hints.Mistakes(r.Chapter.Dir)
+	if err != nil {
+		return err
+	}
+	if len(mistakes) > 0 {
+		fmt.Println("\nThis looks off:")
+		for _, m := range mistakes {
+			rel, _ := filepath.Rel(root, m.Pos.Filename)
+			fmt.Printf("  %s:%d: %s\n", rel, m.Pos.Line, m.Message)
+		}
+	}
+
+	rules, err := hints.Load(r.Chapter.Dir)
+	if err != nil {
+		return err
+	}
+	rule, ok := hints.For(rules, r)
+	if !ok {
+		fmt.Println("\nNo hints for this failure yet. Read the test that fails and what it expects.")
+		return nil
+	}
+	revealed := state.Reveal(r.Chapter.Name, rule)
+	fmt.Printf("\nHints for %s:\n", rule.Test)
+	for i, h := range revealed {
+		fmt.Printf("  %d/%d  %s\n", i+1, len(rule.Hints), h)
+	}
+	if len(revealed) < len(rule.Hints) {
+		fmt.Println("Run `learn hint` again for another hint.")
+	}
+	return state.Save(root)
+}
+
+// runChapter runs the chapter called name and records the result.
+func runChapter(ctx context.Context, root, name string) (chapters.Result, bool, error) {
+	all, err := chapters.Discover(root)
+	if err != nil {
+		return chapters.Result{}, false, err
+	}
+	for _, ch := range all {
+		if ch.Name != name {
+			continue
+		}
+		r, err := chapters.Run(ctx, ch)
+		if err != nil {
+			return chapters.Result{}, false, err
+		}
+		printResult(r)
+		progress, err := chapters.LoadProgress(root)
+		if err != nil {
+			return chapters.Result{}, false, err
+		}
+		progress.Record(r)
+		return r, true, progress.Save(root)
+	}
+	return chapters.Result{}, false, fmt.Errorf("no chapter %q", name)
+}
@@ -0,0 +1,68 @@
+// Command learn walks new hires through the numbered chapters.
+//
+//	learn [run]     run every chapter's tests and show progress
+//	learn next      re-run the first chapter that isn't passing yet
+//	learn new       start a new chapter, e.g. `learn new iteration`
+//	learn docs      browse the chapters' documentation and run their examples
+//	learn quiz      review the README's common gotchas
+//	learn hint      get a hint for the first failing chapter, or a named one
+//	learn coverage  show what the chapters' tests cover and what they miss
+//	learn bench     benchmark this revision and compare it with the last one
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"learn-go/internal/chapters"
+)
+
+type command struct {
+	usage string
+	run   func(root string, args []string) error
+}
+
+var commands = map[string]command{
+	"run":      {"run every chapter's tests and show progress", runAll},
+	"next":     {"re-run the first chapter that isn't passing yet", runNext},
+	"new":      {"start a new chapter, e.g. `learn new iteration`", newChapter},
+	"docs":     {"browse the chapters' documentation and run their examples", serveDocs},
+	"quiz":     {"review the README's common gotchas", runQuiz},
+	"hint":     {"get a hint for the first failing chapter, or a named one", showHint},
+	"coverage": {"show what the chapters' tests cover and what they miss", showCoverage},
+	"bench":    {"benchmark this revision and compare it with the last one", runBench},
+}
+
+func main() {
+	name, args := "run", os.Args[1:]
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	root, err := chapters.FindRoot(".")
+	if err == nil {
+		err = cmd.run(root, args)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "learn:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: learn <command> [args]")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].usage)
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"learn-go/internal/scaffold"
+)
+
+func newChapter(root string, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: learn new <topic>")
+	}
+	ch, err := scaffold.New(root, args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created %s. Run `go test ./%s`, then describe it in README.md.\n", ch.Name, ch.Name)
+	return nil
+}
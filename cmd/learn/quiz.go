@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"learn-go/internal/quiz"
+	"learn-go/internal/sandbox"
+)
+
+func runQuiz(root string, args []string) error {
+	fs := flag.NewFlagSet("quiz", flag.ExitOnError)
+	n := fs.Int("n", 5, "questions per session")
+	verify := fs.Bool("verify", false, "run every question's program and check its answer")
+	fs.Parse(args)
+
+	questions, err := quiz.Load()
+	if err != nil {
+		return err
+	}
+	sb, err := sandbox.New(sandbox.Config{ModuleRoot: root})
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *verify {
+		var errs []error
+		for _, q := range questions {
+			if err := quiz.Verify(ctx, sb, q); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			fmt.Printf("ok  %s\n", q.ID)
+		}
+		return errors.Join(errs...)
+	}
+
+	deck, err := quiz.LoadDeck(root)
+	if err != nil {
+		return err
+	}
+	due := deck.Due(questions, time.Now(), *n)
+	if len(due) == 0 {
+		next, _ := deck.NextDue()
+		fmt.Printf("Nothing to review. Come back in %s.\n", time.Until(next).Round(time.Minute))
+		return nil
+	}
+
+	s := &quiz.Session{In: os.Stdin, Out: os.Stdout, Runner: sb}
+	right, err := s.Ask(ctx, deck, due)
+	if saveErr := deck.Save(root); err == nil {
+		err = saveErr
+	}
+	fmt.Printf("\n%d/%d right this session, %d%% overall.\n", right, len(due), deck.Score())
+	return err
+}
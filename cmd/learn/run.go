@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"learn-go/internal/chapters"
+)
+
+func runAll(root string, args []string) error {
+	all, err := chapters.Discover(root)
+	if err != nil {
+		return err
+	}
+	progress, err := chapters.LoadProgress(root)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	passed := 0
+	for _, ch := range all {
+		r, err := chapters.Run(ctx, ch)
+		if err != nil {
+			return err
+		}
+		progress.Record(r)
+		printResult(r)
+		if r.Passed {
+			passed++
+		}
+	}
+	fmt.Printf("\n%d/%d chapters passing\n", passed, len(all))
+	return progress.Save(root)
+}
+
+func runNext(root string, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	r, ok, err := nextFailing(ctx, root)
+	if err != nil || !ok {
+		return err
+	}
+	fmt.Printf("\n%s\nWork in %s\n", r.Output, r.Chapter.Dir)
+	return nil
+}
+
+// nextFailing runs chapters from the first one not known to pass until
+// one fails, and returns its result. Chapters that were fixed since the
+// last run pass here and we move on.
+func nextFailing(ctx context.Context, root string) (chapters.Result, bool, error) {
+	all, err := chapters.Discover(root)
+	if err != nil {
+		return chapters.Result{}, false, err
+	}
+	progress, err := chapters.LoadProgress(root)
+	if err != nil {
+		return chapters.Result{}, false, err
+	}
+
+	for {
+		ch, ok := progress.Next(all)
+		if !ok {
+			fmt.Println("Every chapter passes. Nice work!")
+			return chapters.Result{}, false, progress.Save(root)
+		}
+
+		r, err := chapters.Run(ctx, ch)
+		if err != nil {
+			return chapters.Result{}, false, err
+		}
+		progress.Record(r)
+		printResult(r)
+		if !r.Passed {
+			return r, true, progress.Save(root)
+		}
+	}
+}
+
+func printResult(r chapters.Result) {
+	if r.Passed {
+		fmt.Printf("PASS  %s\n", r.Chapter.Name)
+		return
+	}
+	fmt.Printf("FAIL  %s\n      %s\n", r.Chapter.Name, r.Failure)
+}
@@ -0,0 +1,40 @@
+// Command playground serves a local Go playground for workshops. Snippets
+// are main packages that may import this module's chapters:
+//
+//	curl --data-binary @main.go localhost:8080/run
+//
+// Snippets run as the user serving the playground, with its files and
+// network: keep it on a workshop network, not the internet.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"learn-go/internal/chapters"
+	"learn-go/internal/sandbox"
+)
+
+func main() {
+	var cfg sandbox.Config
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	flag.DurationVar(&cfg.RunTimeout, "timeout", 0, "wall-clock limit per run (default 5s)")
+	flag.IntVar(&cfg.CPUSeconds, "cpu", 0, "CPU seconds per run (default 5)")
+	flag.Int64Var(&cfg.MemoryBytes, "memory", 0, "memory limit per run in bytes (default 256MiB)")
+	flag.IntVar(&cfg.MaxOutput, "output", 0, "output kept per run in bytes (default 64KiB)")
+	flag.Parse()
+
+	root, err := chapters.FindRoot(".")
+	if err != nil {
+		log.Fatal(err)
+	}
+	cfg.ModuleRoot = root
+	s, err := sandbox.New(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("playground listening on http://%s/run", *addr)
+	log.Fatal(http.ListenAndServe(*addr, sandbox.Handler(s)))
+}
@@ -0,0 +1,45 @@
+// Command readme-check type-checks the Go snippets in Markdown files so
+// they don't rot.
+//
+//	readme-check [file.md ...]
+//
+// With no arguments it checks README.md. Problems are reported against
+// the Markdown line numbers and make the command exit 1.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/importer"
+	"os"
+
+	"learn-go/internal/snippets"
+)
+
+func main() {
+	flag.Parse()
+	files := flag.Args()
+	if len(files) == 0 {
+		files = []string{"README.md"}
+	}
+
+	checker := snippets.NewChecker(importer.Default())
+	failed := false
+	for _, file := range files {
+		markdown, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "readme-check:", err)
+			os.Exit(1)
+		}
+		blocks := snippets.Extract(markdown)
+		for _, b := range blocks {
+			for _, p := range checker.Check(file, b) {
+				fmt.Println(p)
+				failed = true
+			}
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
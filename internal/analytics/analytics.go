@@ -0,0 +1,327 @@
+// Package analytics aggregates greeting events into a rolling window of
+// hourly buckets. Memory use is fixed: the window has a set number of
+// hours and every hour tracks at most MaxLanguages distinct languages.
+package analytics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultHours keeps one week of hourly buckets.
+	DefaultHours = 24 * 7
+
+	// MaxLanguages caps the distinct languages counted per hour. Anything
+	// past the cap is folded into Other, so a caller passing junk languages
+	// can't grow the aggregator without bound.
+	MaxLanguages = 64
+
+	// Other collects languages seen after MaxLanguages was reached.
+	Other = "(other)"
+
+	// Unspecified is recorded for greetings that passed no language.
+	Unspecified = "(none)"
+)
+
+// Event is emitted for every greeting.
+type Event struct {
+	Language string
+	// Fallback is true when the language wasn't supported and the
+	// greeting fell back to English.
+	Fallback bool
+	Time     time.Time
+}
+
+type bucket struct {
+	Hour      time.Time      `json:"hour"`
+	Greetings map[string]int `json:"greetings"`
+	Fallbacks map[string]int `json:"fallbacks,omitempty"`
+}
+
+// Aggregator counts events per language per hour. It is safe for
+// concurrent use.
+type Aggregator struct {
+	mu      sync.Mutex
+	buckets []bucket // ring indexed by hours since the epoch
+	now     func() time.Time
+}
+
+// New returns an Aggregator that remembers the last hours hours.
+func New(hours int) *Aggregator {
+	if hours < 1 {
+		hours = 1
+	}
+	return &Aggregator{buckets: make([]bucket, hours), now: time.Now}
+}
+
+// since returns the oldest hour still in the window. Buckets before it
+// are expired even while their slot hasn't been reused.
+func (a *Aggregator) since() time.Time {
+	return a.now().UTC().Truncate(time.Hour).Add(-time.Duration(len(a.buckets)-1) * time.Hour)
+}
+
+// live reports whether b holds counts for an hour at or after since.
+func (b *bucket) live(since time.Time) bool {
+	return b.Greetings != nil && !b.Hour.Before(since)
+}
+
+// Record counts e in the bucket for its hour. Events older than the
+// window are dropped.
+func (a *Aggregator) Record(e Event) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if e.Time.Before(a.since()) {
+		return
+	}
+	b := a.bucketFor(e.Time)
+	if b == nil {
+		return
+	}
+	language := e.Language
+	if language == "" {
+		language = Unspecified
+	}
+	add(b.Greetings, language, 1)
+	if e.Fallback {
+		add(b.Fallbacks, language, 1)
+	}
+}
+
+// bucketFor returns the bucket for t's hour, recycling the slot if it
+// holds an older hour, or nil if t has already rolled out of the window.
+func (a *Aggregator) bucketFor(t time.Time) *bucket {
+	hour := t.UTC().Truncate(time.Hour)
+	slot := int(hour.Unix()/3600) % len(a.buckets)
+	if slot < 0 {
+		slot += len(a.buckets)
+	}
+
+	b := &a.buckets[slot]
+	switch {
+	case b.Hour.Equal(hour):
+	case b.Hour.After(hour):
+		return nil
+	default:
+		*b = bucket{Hour: hour, Greetings: map[string]int{}, Fallbacks: map[string]int{}}
+	}
+	return b
+}
+
+func add(counts map[string]int, language string, n int) {
+	if _, ok := counts[language]; !ok && len(counts) >= MaxLanguages {
+		language = Other
+	}
+	counts[language] += n
+}
+
+// Row is the count for one language in one hour.
+type Row struct {
+	Hour      time.Time
+	Language  string
+	Greetings int
+	Fallbacks int
+}
+
+// Hourly returns a row per language per hour, oldest hour first.
+func (a *Aggregator) Hourly() []Row {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var rows []Row
+	since := a.since()
+	for _, b := range a.buckets {
+		if !b.live(since) {
+			continue
+		}
+		for language, n := range b.Greetings {
+			rows = append(rows, Row{Hour: b.Hour, Language: language, Greetings: n, Fallbacks: b.Fallbacks[language]})
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].Hour.Equal(rows[j].Hour) {
+			return rows[i].Hour.Before(rows[j].Hour)
+		}
+		return rows[i].Language < rows[j].Language
+	})
+	return rows
+}
+
+// Count is a language and how often it was seen.
+type Count struct {
+	Language string
+	N        int
+}
+
+// TopFallbacks returns the n languages that most often fell back to
+// English across the whole window, most frequent first.
+func (a *Aggregator) TopFallbacks(n int) []Count {
+	a.mu.Lock()
+	totals := map[string]int{}
+	since := a.since()
+	for _, b := range a.buckets {
+		if !b.live(since) {
+			continue
+		}
+		for language, c := range b.Fallbacks {
+			totals[language] += c
+		}
+	}
+	a.mu.Unlock()
+
+	counts := make([]Count, 0, len(totals))
+	for language, c := range totals {
+		counts = append(counts, Count{Language: language, N: c})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].N != counts[j].N {
+			return counts[i].N > counts[j].N
+		}
+		return counts[i].Language < counts[j].Language
+	})
+	if n >= 0 && len(counts) > n {
+		counts = counts[:n]
+	}
+	return counts
+}
+
+// Merge adds every count in other to a.
+func (a *Aggregator) Merge(other *Aggregator) {
+	other.mu.Lock()
+	buckets := make([]bucket, len(other.buckets))
+	copy(buckets, other.buckets)
+	other.mu.Unlock()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	since := a.since()
+	for _, ob := range buckets {
+		if !ob.live(since) {
+			continue
+		}
+		b := a.bucketFor(ob.Hour)
+		if b == nil {
+			continue
+		}
+		for language, n := range ob.Greetings {
+			add(b.Greetings, language, n)
+		}
+		for language, n := range ob.Fallbacks {
+			add(b.Fallbacks, language, n)
+		}
+	}
+}
+
+type file struct {
+	Hours   int      `json:"hours"`
+	Buckets []bucket `json:"buckets"`
+}
+
+// Load replaces a's counts with the ones saved at path. A missing file
+// leaves a empty, and hours that have since rolled out of the window are
+// dropped.
+func (a *Aggregator) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("load %s: %w", path, err)
+	}
+	loaded := &Aggregator{buckets: make([]bucket, len(a.buckets)), now: a.now}
+	since := loaded.since()
+	for _, b := range f.Buckets {
+		if b.Hour.Before(since) {
+			continue
+		}
+		if b.Greetings == nil {
+			b.Greetings = map[string]int{}
+		}
+		if b.Fallbacks == nil {
+			b.Fallbacks = map[string]int{}
+		}
+		if slot := loaded.bucketFor(b.Hour); slot != nil {
+			*slot = b
+		}
+	}
+
+	a.mu.Lock()
+	a.buckets = loaded.buckets
+	a.mu.Unlock()
+	return nil
+}
+
+// Save writes a to path, replacing the file atomically.
+func (a *Aggregator) Save(path string) error {
+	a.mu.Lock()
+	f := file{Hours: len(a.buckets)}
+	since := a.since()
+	for _, b := range a.buckets {
+		if b.live(since) {
+			f.Buckets = append(f.Buckets, b)
+		}
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	a.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Flush merges a into the counts already saved at path and writes the
+// result back, so several short-lived processes can share one file. A
+// lock file next to path keeps concurrent flushes from losing each
+// other's counts.
+func (a *Aggregator) Flush(path string) error {
+	unlock, err := lockFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	saved := New(len(a.buckets))
+	saved.now = a.now
+	if err := saved.Load(path); err != nil {
+		return err
+	}
+	saved.Merge(a)
+	return saved.Save(path)
+}
+
+// DefaultPath is where greeting stats are kept unless told otherwise.
+func DefaultPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "learn-go", "hello-stats.json"), nil
+}
@@ -0,0 +1,219 @@
+package analytics
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+func TestRecord(t *testing.T) {
+	t.Run("counts per language per hour", func(t *testing.T) {
+		a := newAt(24, start.Add(time.Hour))
+		a.Record(Event{Language: "Spanish", Time: start})
+		a.Record(Event{Language: "Spanish", Time: start.Add(10 * time.Minute)})
+		a.Record(Event{Language: "Klingon", Fallback: true, Time: start.Add(time.Hour)})
+
+		got := a.Hourly()
+		want := []Row{
+			{Hour: start, Language: "Spanish", Greetings: 2},
+			{Hour: start.Add(time.Hour), Language: "Klingon", Greetings: 1, Fallbacks: 1},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+	t.Run("hours roll out of the window", func(t *testing.T) {
+		clk := &clock{t: start}
+		a := New(2)
+		a.now = clk.now
+		a.Record(Event{Language: "French", Time: start})
+		clk.advance(2 * time.Hour)
+		a.Record(Event{Language: "French", Time: start.Add(2 * time.Hour)})
+		a.Record(Event{Language: "French", Time: start}) // too old now
+
+		got := a.Hourly()
+		want := []Row{{Hour: start.Add(2 * time.Hour), Language: "French", Greetings: 1}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+	t.Run("languages past the cap are folded", func(t *testing.T) {
+		a := newAt(1, start)
+		for i := 0; i < MaxLanguages+5; i++ {
+			a.Record(Event{Language: fmt.Sprint("lang", i), Time: start})
+		}
+
+		rows := a.Hourly()
+		if len(rows) != MaxLanguages+1 {
+			t.Fatalf("got %d rows want %d", len(rows), MaxLanguages+1)
+		}
+		for _, r := range rows {
+			if r.Language == Other && r.Greetings != 5 {
+				t.Errorf("got %d folded greetings want 5", r.Greetings)
+			}
+		}
+	})
+}
+
+func TestTopFallbacks(t *testing.T) {
+	a := newAt(24, start.Add(5*time.Hour))
+	for i, language := range []string{"German", "Klingon", "German", "Elvish", "German", "Klingon"} {
+		a.Record(Event{Language: language, Fallback: true, Time: start.Add(time.Duration(i) * time.Hour)})
+	}
+	a.Record(Event{Language: "Spanish", Time: start})
+
+	got := a.TopFallbacks(2)
+	want := []Count{{"German", 3}, {"Klingon", 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestFlush(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+
+	for i := 0; i < 2; i++ {
+		a := newAt(24, start)
+		a.Record(Event{Language: "Italian", Fallback: true, Time: start})
+		if err := a.Flush(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a := newAt(24, start)
+	if err := a.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	got := a.Hourly()
+	want := []Row{{Hour: start, Language: "Italian", Greetings: 2, Fallbacks: 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestFlushConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+
+	const flushes = 20
+	var wg sync.WaitGroup
+	for i := 0; i < flushes; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a := newAt(24, start)
+			a.Record(Event{Language: "Italian", Time: start})
+			if err := a.Flush(path); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	a := newAt(24, start)
+	if err := a.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := a.Hourly(); len(got) != 1 || got[0].Greetings != flushes {
+		t.Errorf("got %v, want %d greetings", got, flushes)
+	}
+}
+
+func TestLoadNullCounts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	data := `{"hours": 24, "buckets": [{"hour": "2024-03-01T09:00:00Z", "greetings": null}]}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := newAt(24, start)
+	if err := a.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	a.Record(Event{Language: "German", Time: start})
+	if got := a.Hourly(); len(got) != 1 || got[0].Greetings != 1 {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	clk := &clock{t: start}
+	a := New(24)
+	a.now = clk.now
+	a.Record(Event{Language: "German", Fallback: true, Time: start})
+	if err := a.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	clk.advance(23 * time.Hour)
+	if got := a.Hourly(); len(got) != 1 {
+		t.Errorf("got %v, want the last hour of the window", got)
+	}
+
+	clk.advance(time.Hour)
+	if got := a.Hourly(); len(got) != 0 {
+		t.Errorf("got hourly %v past the window", got)
+	}
+	if got := a.TopFallbacks(10); len(got) != 0 {
+		t.Errorf("got fallbacks %v past the window", got)
+	}
+	a.Record(Event{Language: "German", Time: start})
+	if got := a.Hourly(); len(got) != 0 {
+		t.Errorf("recorded %v past the window", got)
+	}
+
+	loaded := New(24)
+	loaded.now = clk.now
+	if err := loaded.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Hourly(); len(got) != 0 {
+		t.Errorf("loaded %v past the window", got)
+	}
+	if err := loaded.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOutput(t, string(data), "{\n  \"hours\": 24,\n  \"buckets\": null\n}")
+}
+
+func TestWriteTopFallbacks(t *testing.T) {
+	a := newAt(24, start)
+	a.Record(Event{Language: "German", Fallback: true, Time: start})
+
+	var table, csv bytes.Buffer
+	a.WriteTopFallbacks(&table, Table, 10)
+	a.WriteTopFallbacks(&csv, CSV, 10)
+
+	assertOutput(t, table.String(), "RANK  LANGUAGE  FALLBACKS\n1     German    1\n")
+	assertOutput(t, csv.String(), "rank,language,fallbacks\n1,German,1\n")
+}
+
+func assertOutput(t testing.TB, got, want string) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+// newAt returns an Aggregator whose clock is stopped at now.
+func newAt(hours int, now time.Time) *Aggregator {
+	a := New(hours)
+	a.now = func() time.Time { return now }
+	return a
+}
+
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
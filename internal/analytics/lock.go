@@ -0,0 +1,46 @@
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// lockWait is how long Flush waits for another process's lock.
+	lockWait = 10 * time.Second
+
+	// staleLock is how old a lock file has to be before it is taken to
+	// belong to a process that died holding it.
+	staleLock = 30 * time.Second
+)
+
+// lockFile takes path's lock, a file created exclusively next to it, and
+// returns the function that releases it.
+func lockFile(path string) (unlock func(), err error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	lock := path + ".lock"
+	deadline := time.Now().Add(lockWait)
+	for {
+		f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lock) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(lock); err == nil && time.Since(info.ModTime()) > staleLock {
+			os.Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("lock %s: still held after %v", lock, lockWait)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
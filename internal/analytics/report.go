@@ -0,0 +1,63 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Format is how a report is rendered.
+type Format string
+
+const (
+	Table Format = "table"
+	CSV   Format = "csv"
+)
+
+// WriteHourly renders the greetings per language per hour.
+func (a *Aggregator) WriteHourly(w io.Writer, f Format) error {
+	header := []string{"hour", "language", "greetings", "fallbacks"}
+	var rows [][]string
+	for _, r := range a.Hourly() {
+		rows = append(rows, []string{
+			r.Hour.Format(time.RFC3339),
+			r.Language,
+			strconv.Itoa(r.Greetings),
+			strconv.Itoa(r.Fallbacks),
+		})
+	}
+	return write(w, f, header, rows)
+}
+
+// WriteTopFallbacks renders the n languages that most often fell back
+// to English.
+func (a *Aggregator) WriteTopFallbacks(w io.Writer, f Format, n int) error {
+	header := []string{"rank", "language", "fallbacks"}
+	var rows [][]string
+	for i, c := range a.TopFallbacks(n) {
+		rows = append(rows, []string{strconv.Itoa(i + 1), c.Language, strconv.Itoa(c.N)})
+	}
+	return write(w, f, header, rows)
+}
+
+func write(w io.Writer, f Format, header []string, rows [][]string) error {
+	switch f {
+	case CSV:
+		cw := csv.NewWriter(w)
+		cw.Write(header)
+		cw.WriteAll(rows)
+		return cw.Error()
+	case Table, "":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(header, "\t")))
+		for _, r := range rows {
+			fmt.Fprintln(tw, strings.Join(r, "\t"))
+		}
+		return tw.Flush()
+	}
+	return fmt.Errorf("unknown format %q", f)
+}
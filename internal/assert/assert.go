@@ -0,0 +1,12 @@
+// Package assert holds the assertion helpers shared by chapter tests.
+package assert
+
+import "testing"
+
+// Equal fails the test if got and want differ.
+func Equal[T comparable](t testing.TB, got, want T) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %#v want %#v", got, want)
+	}
+}
@@ -0,0 +1,35 @@
+package assert
+
+import (
+	"fmt"
+	"testing"
+)
+
+// recorder captures failures instead of failing the real test.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestEqual(t *testing.T) {
+	t.Run("equal values pass", func(t *testing.T) {
+		r := &recorder{TB: t}
+		Equal(r, "Hello, Max", "Hello, Max")
+		if len(r.failures) != 0 {
+			t.Errorf("got failures %q", r.failures)
+		}
+	})
+	t.Run("different values fail", func(t *testing.T) {
+		r := &recorder{TB: t}
+		Equal(r, 3, 4)
+		if len(r.failures) != 1 || r.failures[0] != "got 3 want 4" {
+			t.Errorf("got failures %q", r.failures)
+		}
+	})
+}
@@ -0,0 +1,112 @@
+// Package bench runs the module's benchmarks several times, keeps the
+// results per git revision and tells whether a change made them slower,
+// the way benchstat does: a Mann-Whitney U test on the samples and a
+// confidence interval around each median.
+package bench
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"learn-go/internal/chapters"
+)
+
+// The metrics compared between runs. Lower is better for both.
+const (
+	NsPerOp     = "ns/op"
+	AllocsPerOp = "allocs/op"
+)
+
+// Samples holds every measurement of one benchmark, by metric, such as
+// "ns/op" or "allocs/op".
+type Samples map[string][]float64
+
+// Options tells Run which benchmarks to run and how.
+type Options struct {
+	Packages  []string // default ./...
+	Bench     string   // regexp, default "."
+	Count     int      // runs of each benchmark, default 10
+	Benchtime string   // passed to -benchtime if set
+}
+
+// Run runs `go test -bench` in root and returns the samples of each
+// benchmark, keyed like "02-integers/Add-8".
+func Run(ctx context.Context, root string, opts Options) (map[string]Samples, error) {
+	if len(opts.Packages) == 0 {
+		opts.Packages = []string{"./..."}
+	}
+	if opts.Bench == "" {
+		opts.Bench = "."
+	}
+	if opts.Count < 1 {
+		opts.Count = 10
+	}
+
+	args := []string{"test", "-run=^$", "-bench=" + opts.Bench, "-benchmem", "-count=" + strconv.Itoa(opts.Count)}
+	if opts.Benchtime != "" {
+		args = append(args, "-benchtime="+opts.Benchtime)
+	}
+	cmd := exec.CommandContext(ctx, "go", append(args, opts.Packages...)...)
+	cmd.Dir = root
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go test -bench: %w\n%s%s", err, out, stderr.Bytes())
+	}
+
+	module, err := chapters.ModulePath(root)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(bytes.NewReader(out), module)
+}
+
+var resultLine = regexp.MustCompile(`^Benchmark(\S+)\s+\d+\s+(.*)$`)
+
+// Parse reads `go test -bench` output. Benchmarks are keyed by their
+// package, relative to module, and their name without "Benchmark". The
+// name keeps its GOMAXPROCS suffix, such as "-8", so runs with different
+// -cpu values are kept apart and only compared with their like.
+func Parse(r io.Reader, module string) (map[string]Samples, error) {
+	results := map[string]Samples{}
+	pkg := ""
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		if p, ok := strings.CutPrefix(line, "pkg: "); ok {
+			pkg = strings.TrimPrefix(strings.TrimPrefix(p, module), "/")
+			continue
+		}
+		m := resultLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		name := m[1]
+		if pkg != "" {
+			name = pkg + "/" + name
+		}
+		s := results[name]
+		if s == nil {
+			s = Samples{}
+			results[name] = s
+		}
+		// The rest of the line is pairs of value and unit.
+		fields := strings.Fields(m[2])
+		for i := 0; i+1 < len(fields); i += 2 {
+			v, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("bad benchmark line %q", line)
+			}
+			s[fields[i+1]] = append(s[fields[i+1]], v)
+		}
+	}
+	return results, sc.Err()
+}
@@ -0,0 +1,116 @@
+package bench
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+const output = `goos: linux
+goarch: amd64
+pkg: learn-go/01-hello-world
+BenchmarkHello-8   	 6197388	       186.3 ns/op	      16 B/op	       1 allocs/op
+BenchmarkHello-8   	 5297859	       236.6 ns/op	      16 B/op	       1 allocs/op
+PASS
+ok  	learn-go/01-hello-world	2.412s
+pkg: learn-go/02-integers
+BenchmarkAdd/small-8 	523901318	         1.968 ns/op	       0 B/op	       0 allocs/op
+BenchmarkAdd/small   	612345678	         1.702 ns/op	       0 B/op	       0 allocs/op
+BenchmarkAdd/small-4 	587654321	         1.801 ns/op	       0 B/op	       0 allocs/op
+PASS
+`
+
+func TestParse(t *testing.T) {
+	results, err := Parse(strings.NewReader(output), "learn-go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hello := results["01-hello-world/Hello-8"]
+	if len(hello[NsPerOp]) != 2 || hello[NsPerOp][1] != 236.6 || hello[AllocsPerOp][0] != 1 {
+		t.Errorf("got Hello %v", hello)
+	}
+	// One sample per GOMAXPROCS: -cpu=1,4,8 are three benchmarks.
+	for _, name := range []string{"02-integers/Add/small-8", "02-integers/Add/small", "02-integers/Add/small-4"} {
+		if add := results[name]; len(add[NsPerOp]) != 1 {
+			t.Errorf("got %s %v from %v", name, add, results)
+		}
+	}
+}
+
+func TestMannWhitney(t *testing.T) {
+	cases := []struct {
+		name string
+		x, y []float64
+		want float64
+	}{
+		// 2 of the C(10,5) = 252 orderings are this far apart.
+		{"exact", []float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10}, 2.0 / 252},
+		{"exact, interleaved", []float64{1, 3, 5, 7, 9}, []float64{2, 4, 6, 8, 10}, 0.690476},
+		{"ties", []float64{0, 0, 0, 0, 0}, []float64{1, 1, 1, 1, 1}, 0.003977},
+		{"all equal", []float64{1, 1, 1}, []float64{1, 1, 1}, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := MannWhitney(c.x, c.y); math.Abs(got-c.want) > 1e-5 {
+				t.Errorf("got p=%.6f want %.6f", got, c.want)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	s := Summarize([]float64{10, 1, 9, 2, 8, 3, 7, 4, 6, 5})
+	if s.Median != 5.5 || s.Lo != 2 || s.Hi != 9 {
+		t.Errorf("got %+v, want median 5.5 in [2, 9]", s)
+	}
+	if s := Summarize([]float64{3, 1, 2}); s.Lo != 1 || s.Hi != 3 {
+		t.Errorf("got %+v, want the whole range for few samples", s)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	old := map[string]Samples{
+		"Hello": {NsPerOp: {100, 101, 99, 100, 102, 98}, AllocsPerOp: {1, 1, 1, 1, 1, 1}},
+		"Gone":  {NsPerOp: {1}},
+	}
+	new := map[string]Samples{
+		"Hello": {NsPerOp: {120, 121, 119, 122, 118, 120}, AllocsPerOp: {1, 1, 1, 1, 1, 1}},
+	}
+	deltas := Compare(old, new)
+	if len(deltas) != 2 || deltas[0].Metric != NsPerOp {
+		t.Fatalf("got %+v", deltas)
+	}
+	if d := deltas[0]; !d.Regression() || math.Abs(d.Change-0.2) > 1e-9 {
+		t.Errorf("ns/op: got %+v", d)
+	}
+	if d := deltas[1]; d.Significant() {
+		t.Errorf("allocs/op: got %+v", d)
+	}
+
+	var out strings.Builder
+	WriteComparison(&out, "abc", "def", deltas)
+	if !strings.Contains(out.String(), "+20.00% (p=0.005 n=6+6)  regression") {
+		t.Errorf("got\n%s", out.String())
+	}
+}
+
+func TestHistory(t *testing.T) {
+	root := t.TempDir()
+	now := time.Now()
+	h, _ := LoadHistory(root)
+	h.Add(&Record{Rev: "old", Time: now.Add(-2 * time.Hour)})
+	h.Add(&Record{Rev: "newer", Time: now.Add(-time.Hour)})
+	h.Add(&Record{Rev: "head", Time: now})
+	if err := h.Save(root); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := LoadHistory(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base, ok := h.Baseline("head"); !ok || base.Rev != "newer" {
+		t.Errorf("got baseline %+v", base)
+	}
+}
@@ -0,0 +1,88 @@
+package bench
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+)
+
+// Delta compares one metric of a benchmark between two runs.
+type Delta struct {
+	Name     string
+	Metric   string
+	Old, New Summary
+	// Change is the relative change of the median, e.g. 0.12 when the
+	// new run is 12% slower.
+	Change float64
+	P      float64
+}
+
+// Significant reports whether the change is unlikely to be noise.
+func (d Delta) Significant() bool { return d.P < Alpha }
+
+// Regression reports whether the metric got significantly worse.
+func (d Delta) Regression() bool { return d.Significant() && d.Change > 0 }
+
+// Compare compares the ns/op and allocs/op of the benchmarks both runs
+// have, sorted by name.
+func Compare(old, new map[string]Samples) []Delta {
+	var deltas []Delta
+	for name, o := range old {
+		n, ok := new[name]
+		if !ok {
+			continue
+		}
+		for _, metric := range []string{NsPerOp, AllocsPerOp} {
+			if len(o[metric]) == 0 || len(n[metric]) == 0 {
+				continue
+			}
+			d := Delta{
+				Name:   name,
+				Metric: metric,
+				Old:    Summarize(o[metric]),
+				New:    Summarize(n[metric]),
+				P:      MannWhitney(o[metric], n[metric]),
+			}
+			switch {
+			case d.Old.Median != 0:
+				d.Change = d.New.Median/d.Old.Median - 1
+			case d.New.Median != 0:
+				d.Change = math.Inf(1)
+			}
+			deltas = append(deltas, d)
+		}
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		if deltas[i].Name != deltas[j].Name {
+			return deltas[i].Name < deltas[j].Name
+		}
+		return deltas[i].Metric > deltas[j].Metric // ns/op first
+	})
+	return deltas
+}
+
+// WriteComparison renders deltas as a table like benchstat's. Changes
+// that aren't significant show as "~".
+func WriteComparison(w io.Writer, oldRev, newRev string, deltas []Delta) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "BENCHMARK\t%s\t%s\tDELTA\t\n", oldRev, newRev)
+	for _, d := range deltas {
+		change := "~"
+		if d.Significant() {
+			change = fmt.Sprintf("%+.2f%%", 100*d.Change)
+		}
+		note := ""
+		if d.Regression() {
+			note = "regression"
+		}
+		fmt.Fprintf(tw, "%s %s\t%s\t%s\t%s (p=%.3f n=%d+%d)\t%s\n",
+			d.Name, d.Metric, formatSummary(d.Old), formatSummary(d.New), change, d.P, d.Old.N, d.New.N, note)
+	}
+	return tw.Flush()
+}
+
+func formatSummary(s Summary) string {
+	return fmt.Sprintf("%.4g ± %.0f%%", s.Median, 100*s.Spread())
+}
@@ -0,0 +1,104 @@
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// HistoryFile is where results are kept, relative to the module root.
+const HistoryFile = ".learn/bench.json"
+
+// Record is one `learn bench` run.
+type Record struct {
+	Rev        string             `json:"rev"`
+	Time       time.Time          `json:"time"`
+	GoVersion  string             `json:"go"`
+	Benchmarks map[string]Samples `json:"benchmarks"`
+}
+
+// History holds the latest record of each git revision.
+type History struct {
+	Records map[string]*Record `json:"records"`
+}
+
+// LoadHistory reads the history saved under root.
+func LoadHistory(root string) (*History, error) {
+	h := &History{Records: map[string]*Record{}}
+	data, err := os.ReadFile(filepath.Join(root, HistoryFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("load bench history: %w", err)
+	}
+	if h.Records == nil {
+		h.Records = map[string]*Record{}
+	}
+	return h, nil
+}
+
+// Save writes h under root.
+func (h *History) Save(root string) error {
+	path := filepath.Join(root, HistoryFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Add stores r, replacing any earlier record of the same revision.
+func (h *History) Add(r *Record) {
+	h.Records[r.Rev] = r
+}
+
+// Baseline returns the most recent record of another revision than rev,
+// the one to compare rev against.
+func (h *History) Baseline(rev string) (*Record, bool) {
+	var base *Record
+	for _, r := range h.Records {
+		if r.Rev != rev && (base == nil || r.Time.After(base.Time)) {
+			base = r
+		}
+	}
+	return base, base != nil
+}
+
+// Revision names the checked out git revision of root, with a "-dirty"
+// suffix if tracked files have changed since.
+func Revision(ctx context.Context, root string) (string, error) {
+	git := func(args ...string) (string, error) {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = root
+		out, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("git %s: %w", args[0], err)
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+	rev, err := git("rev-parse", "--short", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	status, err := git("status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return "", err
+	}
+	if status != "" {
+		rev += "-dirty"
+	}
+	return rev, nil
+}
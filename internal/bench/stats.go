@@ -0,0 +1,175 @@
+package bench
+
+import (
+	"math"
+	"slices"
+)
+
+// Alpha is the significance level: a change is only reported when a
+// difference this large would show up by chance less than 5% of the time.
+const Alpha = 0.05
+
+// Summary is the median of a sample and a confidence interval around it.
+type Summary struct {
+	N      int
+	Median float64
+	Lo, Hi float64
+}
+
+// Summarize returns the median of xs with its 1-Alpha confidence
+// interval. The interval comes from order statistics, so it makes no
+// assumption about how benchmark times are distributed. With fewer than
+// six samples no interval is that confident, and it spans every sample.
+func Summarize(xs []float64) Summary {
+	if len(xs) == 0 {
+		return Summary{}
+	}
+	s := slices.Clone(xs)
+	slices.Sort(s)
+	n := len(s)
+	sum := Summary{N: n, Median: (s[(n-1)/2] + s[n/2]) / 2, Lo: s[0], Hi: s[n-1]}
+
+	// The interval is [s[k-1], s[n-k]] for the largest k with
+	// P(Binomial(n, 1/2) < k) <= Alpha/2.
+	cdf, k := 0.0, 0
+	for i := 0; i < n/2; i++ {
+		cdf += binomial(n, i) / math.Exp2(float64(n))
+		if cdf > Alpha/2 {
+			break
+		}
+		k = i + 1
+	}
+	if k > 0 {
+		sum.Lo, sum.Hi = s[k-1], s[n-k]
+	}
+	return sum
+}
+
+// Spread is the half-width of the interval relative to the median, the
+// "± 3%" benchstat prints.
+func (s Summary) Spread() float64 {
+	if s.Median == 0 {
+		return 0
+	}
+	return math.Max(s.Median-s.Lo, s.Hi-s.Median) / s.Median
+}
+
+func binomial(n, k int) float64 {
+	r := 1.0
+	for i := 1; i <= k; i++ {
+		r = r * float64(n-k+i) / float64(i)
+	}
+	return r
+}
+
+// MannWhitney returns the two-sided p-value of the Mann-Whitney U test,
+// the chance of seeing samples as different as x and y if they came from
+// the same distribution. Small samples without ties use the exact
+// distribution of U, others its normal approximation corrected for ties.
+func MannWhitney(x, y []float64) float64 {
+	n1, n2 := len(x), len(y)
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+
+	// Rank the pooled samples, giving ties their average rank.
+	type value struct {
+		v     float64
+		fromX bool
+	}
+	pooled := make([]value, 0, n1+n2)
+	for _, v := range x {
+		pooled = append(pooled, value{v, true})
+	}
+	for _, v := range y {
+		pooled = append(pooled, value{v, false})
+	}
+	slices.SortFunc(pooled, func(a, b value) int {
+		switch {
+		case a.v < b.v:
+			return -1
+		case a.v > b.v:
+			return 1
+		}
+		return 0
+	})
+	var rankX, tieTerm float64
+	for i := 0; i < len(pooled); {
+		j := i
+		for j < len(pooled) && pooled[j].v == pooled[i].v {
+			j++
+		}
+		rank := float64(i+j+1) / 2 // ranks i+1..j averaged
+		for _, p := range pooled[i:j] {
+			if p.fromX {
+				rankX += rank
+			}
+		}
+		t := float64(j - i)
+		tieTerm += t*t*t - t
+		i = j
+	}
+
+	u := rankX - float64(n1*(n1+1))/2
+	uMin := math.Min(u, float64(n1*n2)-u)
+
+	if tieTerm == 0 && n1+n2 <= 50 {
+		dist := uDistribution(n1, n2)
+		total, tail := 0.0, 0.0
+		for i, c := range dist {
+			total += c
+			if float64(i) <= uMin {
+				tail += c
+			}
+		}
+		return math.Min(1, 2*tail/total)
+	}
+
+	n := float64(n1 + n2)
+	mean := float64(n1*n2) / 2
+	variance := float64(n1*n2) / 12 * ((n + 1) - tieTerm/(n*(n-1)))
+	if variance == 0 {
+		return 1
+	}
+	z := (math.Abs(u-mean) - 0.5) / math.Sqrt(variance)
+	if z <= 0 {
+		return 1
+	}
+	return math.Erfc(z / math.Sqrt2)
+}
+
+// uDistribution returns how many orderings of n1 and n2 distinct values
+// give each value of U. These are the coefficients of the Gaussian
+// binomial coefficient [n1+n2 choose n1], built with its Pascal rule
+// [a choose b] = [a-1 choose b-1] + q^b [a-1 choose b].
+func uDistribution(n1, n2 int) []float64 {
+	// row[b] is the polynomial [a choose b] for the current a.
+	row := make([][]float64, n1+1)
+	row[0] = []float64{1}
+	for a := 1; a <= n1+n2; a++ {
+		next := make([][]float64, n1+1)
+		for b := 0; b <= min(a, n1); b++ {
+			var p []float64
+			if b > 0 && row[b-1] != nil {
+				p = addPoly(p, row[b-1], 0)
+			}
+			if b < a && row[b] != nil {
+				p = addPoly(p, row[b], b)
+			}
+			next[b] = p
+		}
+		row = next
+	}
+	return row[n1]
+}
+
+// addPoly adds q^shift * q to p.
+func addPoly(p, q []float64, shift int) []float64 {
+	if n := len(q) + shift; len(p) < n {
+		p = append(p, make([]float64, n-len(p))...)
+	}
+	for i, c := range q {
+		p[i+shift] += c
+	}
+	return p
+}
@@ -0,0 +1,202 @@
+// Package braille renders text as Unicode braille patterns, for
+// refreshable braille displays. English, Spanish and French have Grade 1
+// tables, which spell out every letter, with the accented letters, capital
+// and number signs of each language's code. English also has Grade 2,
+// which contracts common words and groups of letters.
+package braille
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Grade is how much braille is contracted.
+type Grade int
+
+const (
+	Grade1 Grade = 1 // uncontracted
+	Grade2 Grade = 2 // contracted
+)
+
+// blank is the braille pattern with no dots, which stands for a space.
+const blank = 0x2800
+
+// Render renders text in the braille code of language, a BCP 47 tag such
+// as "en" or "fr-CA", and grade. It returns an error for a language
+// without a table for grade and for a character the table lacks.
+func Render(text, language string, grade Grade) (string, error) {
+	base, _, _ := strings.Cut(strings.ToLower(language), "-")
+	t, ok := tables[base]
+	switch {
+	case !ok:
+		return "", fmt.Errorf("braille: no table for %q", language)
+	case grade != Grade1 && grade != Grade2:
+		return "", fmt.Errorf("braille: no grade %d", grade)
+	case grade == Grade2 && t.grade2 == nil:
+		return "", fmt.Errorf("braille: no Grade 2 table for %q", language)
+	}
+	r := renderer{table: t, text: []rune(text)}
+	if grade == Grade2 {
+		r.grade2 = t.grade2
+	}
+	return r.render()
+}
+
+type renderer struct {
+	*table
+	grade2 *contractions // nil for Grade 1
+	text   []rune
+	b      strings.Builder
+}
+
+func (r *renderer) render() (string, error) {
+	for i := 0; i < len(r.text); {
+		c := r.text[i]
+		switch {
+		case unicode.IsLetter(c):
+			j := r.wordEnd(i)
+			if err := r.word(i, j); err != nil {
+				return "", err
+			}
+			i = j
+		case isDigit(c):
+			i = r.number(i)
+		case unicode.IsSpace(c):
+			r.b.WriteRune(blank)
+			i++
+		default:
+			p, ok := r.punct[c]
+			if !ok {
+				return "", fmt.Errorf("braille: no cells for %q", c)
+			}
+			r.b.WriteString(p)
+			i++
+		}
+	}
+	return r.b.String(), nil
+}
+
+// wordEnd returns the end of the word starting at i: letters, and
+// apostrophes between them.
+func (r *renderer) wordEnd(i int) int {
+	for i < len(r.text) {
+		switch {
+		case unicode.IsLetter(r.text[i]):
+		case isApostrophe(r.text[i]) && i+1 < len(r.text) && unicode.IsLetter(r.text[i+1]):
+		default:
+			return i
+		}
+		i++
+	}
+	return i
+}
+
+func isApostrophe(c rune) bool {
+	return c == '\'' || c == '’'
+}
+
+// word renders the word text[i:j].
+func (r *renderer) word(i, j int) error {
+	w := r.text[i:j]
+	lower := []rune(strings.ToLower(string(w)))
+	caps := len(w) > 1 && strings.ToUpper(string(w)) == string(w)
+	if caps {
+		r.b.WriteString(r.capital + r.capital)
+	}
+	// capital writes the capital sign for w[k:k+n], which may only be
+	// contracted if it is in lower case after its first letter.
+	capital := func(k, n int) bool {
+		if caps {
+			return true
+		}
+		for _, c := range w[k+1 : k+n] {
+			if unicode.IsUpper(c) {
+				return false
+			}
+		}
+		if unicode.IsUpper(w[k]) {
+			r.b.WriteString(r.capital)
+		}
+		return true
+	}
+
+	// A word next to a number doesn't stand alone.
+	start := 0
+	alone := (i == 0 || !isDigit(r.text[i-1])) && (j == len(r.text) || !isDigit(r.text[j]))
+	if c := r.grade2; c != nil && alone {
+		if s, ok := c.words[string(lower)]; ok && capital(0, len(w)) {
+			r.b.WriteString(s)
+			return nil
+		}
+		if s, ok := c.lowerWords[string(lower)]; ok && r.spaced(i, j) && capital(0, len(w)) {
+			r.b.WriteString(s)
+			return nil
+		}
+		if k, ok := c.beforeApostrophe(lower); ok && capital(0, k) {
+			// "it's" and "can't": the wordsign, then the rest as it is.
+			r.b.WriteString(c.words[string(lower[:k])])
+			start = k
+		} else if len(w) == 1 && !strings.ContainsRune("aio", lower[0]) {
+			// A letter on its own would read as its wordsign.
+			r.b.WriteString(r.letterSign)
+		}
+	}
+
+	for k := start; k < len(w); {
+		if r.grade2 != nil {
+			if g, ok := r.grade2.match(lower, k); ok && capital(k, len(g.letters)) {
+				r.b.WriteString(g.braille)
+				k += len(g.letters)
+				continue
+			}
+		}
+		if isApostrophe(w[k]) {
+			p, ok := r.punct[w[k]]
+			if !ok {
+				return fmt.Errorf("braille: no cells for %q", w[k])
+			}
+			r.b.WriteString(p)
+			k++
+			continue
+		}
+		s, ok := r.letters[lower[k]]
+		if !ok {
+			return fmt.Errorf("braille: no cells for %q", w[k])
+		}
+		capital(k, 1)
+		r.b.WriteString(s)
+		k++
+	}
+	return nil
+}
+
+// spaced reports whether text[i:j] has spaces, or the start or end of the
+// text, on both sides.
+func (r *renderer) spaced(i, j int) bool {
+	return (i == 0 || unicode.IsSpace(r.text[i-1])) && (j == len(r.text) || unicode.IsSpace(r.text[j]))
+}
+
+// number renders the number starting at i, with its decimal separators,
+// and returns where it ends.
+func (r *renderer) number(i int) int {
+	r.b.WriteString(r.numeric)
+	for ; i < len(r.text); i++ {
+		c := r.text[i]
+		if isDigit(c) {
+			r.b.WriteString(r.digits[c-'0'])
+		} else if (c == '.' || c == ',') && i+1 < len(r.text) && isDigit(r.text[i+1]) {
+			r.b.WriteString(r.punct[c])
+		} else {
+			break
+		}
+	}
+	if i < len(r.text) && r.text[i] >= 'a' && r.text[i] <= 'j' {
+		r.b.WriteString(r.letterSign)
+	}
+	return i
+}
+
+func isDigit(c rune) bool {
+	return c >= '0' && c <= '9'
+}
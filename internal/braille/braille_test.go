@@ -0,0 +1,157 @@
+package braille
+
+import (
+	"maps"
+	"testing"
+)
+
+// rendered are texts and how Render writes them. They are not published
+// transcriptions: each was worked out by hand from the rules named above
+// its group, so they catch regressions but can't catch a misreading of a
+// rule. Samples copied from a published transcription, with its page,
+// belong in their own table.
+var rendered = []struct {
+	text, language string
+	grade          Grade
+	want           string
+}{
+	// Rules of Unified English Braille, 2nd edition (ICEB, 2013): §8
+	// capitals, §7 punctuation.
+	{"Hello, World", "en", Grade1, "⠠⠓⠑⠇⠇⠕⠂⠀⠠⠺⠕⠗⠇⠙"},
+	{"HELLO", "en", Grade1, "⠠⠠⠓⠑⠇⠇⠕"},
+	{"Braille", "en", Grade1, "⠠⠃⠗⠁⠊⠇⠇⠑"},
+	// UEB §6 numeric mode, and §5 the grade 1 indicator before a letter
+	// a to j after a number.
+	{"I have 12 books.", "en", Grade1, "⠠⠊⠀⠓⠁⠧⠑⠀⠼⠁⠃⠀⠃⠕⠕⠅⠎⠲"},
+	{"3.5", "en", Grade1, "⠼⠉⠲⠑"},
+	{"2b", "en", Grade1, "⠼⠃⠰⠃"},
+	// UEB §4.2 modifiers: see also TestModifiers.
+	{"Zoë", "en", Grade1, "⠠⠵⠕⠘⠒⠑"},
+	{"café", "en", Grade1, "⠉⠁⠋⠘⠌⠑"},
+	// UEB §10 contractions: wordsigns, groupsigns and initial-letter
+	// contractions, and §5 the grade 1 indicator before a letter that
+	// would read as a wordsign.
+	{"Hello, World", "en", Grade2, "⠠⠓⠑⠇⠇⠕⠂⠀⠠⠸⠺"},
+	{"the cat and the dog", "en", Grade2, "⠮⠀⠉⠁⠞⠀⠯⠀⠮⠀⠙⠕⠛"},
+	{"you can do it", "en", Grade2, "⠽⠀⠉⠀⠙⠀⠭"},
+	{"The child", "en", Grade2, "⠠⠮⠀⠡"},
+	{"THE END", "en", Grade2, "⠠⠠⠮⠀⠠⠠⠢⠙"},
+	{"singing", "en", Grade2, "⠎⠬⠬"},
+	{"other", "en", Grade2, "⠕⠮⠗"},
+	{"shout", "en", Grade2, "⠩⠳⠞"},
+	{"team", "en", Grade2, "⠞⠂⠍"},
+	{"wear", "en", Grade2, "⠺⠑⠜"},
+	{"ear", "en", Grade2, "⠑⠜"},
+	{"when", "en", Grade2, "⠱⠢"},
+	{"where there", "en", Grade2, "⠐⠱⠀⠐⠮"},
+	{"one day", "en", Grade2, "⠐⠕⠀⠐⠙"},
+	{"his book", "en", Grade2, "⠦⠀⠃⠕⠕⠅"},
+	{"his.", "en", Grade2, "⠓⠊⠎⠲"},
+	{"Max B", "en", Grade2, "⠠⠍⠁⠭⠀⠰⠠⠃"},
+	{"a cat", "en", Grade2, "⠁⠀⠉⠁⠞"},
+	// UEB §10.1: a wordsign before an apostrophe and d, ll, re, s, t or
+	// ve.
+	{"it's", "en", Grade2, "⠭⠄⠎"},
+	{"It's", "en", Grade2, "⠠⠭⠄⠎"},
+	{"you're", "en", Grade2, "⠽⠄⠗⠑"},
+	{"can't", "en", Grade2, "⠉⠄⠞"},
+	{"o'clock", "en", Grade2, "⠕⠄⠉⠇⠕⠉⠅"},
+	// Comisión Braille Española, Documento técnico B 1: Signografía
+	// básica (ONCE).
+	{"Hola, Mundo", "es", Grade1, "⠨⠓⠕⠇⠁⠂⠀⠨⠍⠥⠝⠙⠕"},
+	{"¡Hola!", "es", Grade1, "⠖⠨⠓⠕⠇⠁⠖"},
+	{"¿Qué tal?", "es", Grade1, "⠢⠨⠟⠥⠮⠀⠞⠁⠇⠢"},
+	{"Año", "es", Grade1, "⠨⠁⠻⠕"},
+	{"Adiós, pingüino", "es", Grade1, "⠨⠁⠙⠊⠬⠎⠂⠀⠏⠊⠝⠛⠳⠊⠝⠕"},
+	{"D'Angelo", "es", Grade1, "⠨⠙⠄⠨⠁⠝⠛⠑⠇⠕"},
+	{"D’Angelo", "es", Grade1, "⠨⠙⠄⠨⠁⠝⠛⠑⠇⠕"},
+	// Code braille français uniformisé, 2nd edition (Commission
+	// Évolution du Braille Français, 2008).
+	{"Bonjour, le monde", "fr", Grade1, "⠨⠃⠕⠝⠚⠕⠥⠗⠂⠀⠇⠑⠀⠍⠕⠝⠙⠑"},
+	{"Élodie", "fr", Grade1, "⠨⠿⠇⠕⠙⠊⠑"},
+	{"François", "fr", Grade1, "⠨⠋⠗⠁⠝⠯⠕⠊⠎"},
+	{"l'été", "fr", Grade1, "⠇⠄⠿⠞⠿"},
+	{"2024", "fr", Grade1, "⠠⠣⠼⠣⠹"},
+	{"Bonjour, Zoë", "fr-CA", Grade1, "⠨⠃⠕⠝⠚⠕⠥⠗⠂⠀⠨⠵⠕⠫"},
+}
+
+func TestRender(t *testing.T) {
+	for _, g := range rendered {
+		got, err := Render(g.text, g.language, g.grade)
+		if err != nil {
+			t.Errorf("Render(%q, %q, %d): %v", g.text, g.language, g.grade, err)
+			continue
+		}
+		if got != g.want {
+			t.Errorf("Render(%q, %q, %d):\ngot  %s\nwant %s", g.text, g.language, g.grade, got, g.want)
+		}
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	cases := []struct {
+		text, language string
+		grade          Grade
+	}{
+		{"Hallo", "de", Grade1},
+		{"Hola", "es", Grade2},
+		{"Hello", "en", Grade(3)},
+		{"你好", "en", Grade1},
+		{"Zoë", "es", Grade1},
+		{"Hello @ Max", "en", Grade2},
+	}
+	for _, c := range cases {
+		if got, err := Render(c.text, c.language, c.grade); err == nil {
+			t.Errorf("Render(%q, %q, %d) = %q, want an error", c.text, c.language, c.grade, got)
+		}
+	}
+
+	t.Run("apostrophe missing from the table", func(t *testing.T) {
+		es := tables["es"]
+		saved := es.punct
+		t.Cleanup(func() { es.punct = saved })
+		es.punct = maps.Clone(saved)
+		delete(es.punct, '’')
+		if got, err := Render("D’Angelo", "es", Grade1); err == nil {
+			t.Errorf("got %q, want an error", got)
+		}
+	})
+}
+
+func TestTables(t *testing.T) {
+	if got := cells("1 12 14"); got != "⠁⠃⠉" {
+		t.Errorf("cells: got %s", got)
+	}
+	for lang, tab := range tables {
+		seen := map[string]rune{}
+		for r, s := range tab.letters {
+			if other, ok := seen[s]; ok && len([]rune(s)) == 1 {
+				t.Errorf("%s: %q and %q are both %s", lang, r, other, s)
+			}
+			seen[s] = r
+		}
+	}
+}
+
+// TestModifiers renders a letter with each modifier of UEB §4.2.
+func TestModifiers(t *testing.T) {
+	cases := []struct {
+		letter string
+		want   string
+	}{
+		{"à", "⠘⠡⠁"}, // grave
+		{"é", "⠘⠌⠑"}, // acute
+		{"ô", "⠘⠩⠕"}, // circumflex
+		{"ñ", "⠘⠻⠝"}, // tilde
+		{"ü", "⠘⠒⠥"}, // diaeresis
+		{"å", "⠘⠫⠁"}, // ring
+		{"ç", "⠘⠯⠉"}, // cedilla
+		{"ø", "⠈⠡⠕"}, // stroke
+	}
+	for _, c := range cases {
+		got, err := Render(c.letter, "en", Grade1)
+		if err != nil || got != c.want {
+			t.Errorf("Render(%q): got %s, %v want %s", c.letter, got, err, c.want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package braille
+
+import (
+	"slices"
+	"strings"
+)
+
+// contractions are a Grade 2 table: words and groups of letters written
+// with fewer cells.
+type contractions struct {
+	// words are contracted only when they stand alone.
+	words map[string]string
+	// lowerWords are also only contracted where no punctuation touches
+	// them, since their cells have no dots 1 or 4 and would read as
+	// punctuation.
+	lowerWords map[string]string
+	groups     []group // longest first
+}
+
+type group struct {
+	letters, braille string
+	where            position
+	lower            bool // a lower groupsign, which gives way to strong ones
+}
+
+// position is where in a word a group may be contracted.
+type position int
+
+const (
+	anywhere position = iota
+	notFirst          // "ing" doesn't start a word
+	middle            // "ea" and doubled letters are neither first nor last
+)
+
+// englishContractions are the commonest contractions of Grade 2 Unified
+// English Braille: the alphabetic, strong and lower wordsigns, the strong
+// contractions and groupsigns, "en", "in", "ea" and doubled letters, and
+// the initial-letter and final-letter contractions as whole words.
+// Shortforms and the rules about syllables are left out, so a few words
+// are contracted less than a transcriber would.
+var englishContractions = newContractions(
+	map[string]string{
+		// Alphabetic wordsigns.
+		"but": "12", "can": "14", "do": "145", "every": "15", "from": "124", "go": "1245",
+		"have": "125", "just": "245", "knowledge": "13", "like": "123", "more": "134",
+		"not": "1345", "people": "1234", "quite": "12345", "rather": "1235", "so": "234",
+		"that": "2345", "us": "136", "very": "1236", "will": "2456", "it": "1346",
+		"you": "13456", "as": "1356",
+		// Strong wordsigns and contractions.
+		"child": "16", "shall": "146", "this": "1456", "which": "156", "out": "1256",
+		"still": "34", "and": "12346", "for": "123456", "of": "12356", "the": "2346",
+		"with": "23456",
+		// Initial-letter contractions.
+		"day": "5 145", "ever": "5 15", "father": "5 124", "here": "5 125", "know": "5 13",
+		"lord": "5 123", "mother": "5 134", "name": "5 1345", "one": "5 135", "part": "5 1234",
+		"question": "5 12345", "right": "5 1235", "some": "5 234", "time": "5 2345",
+		"under": "5 136", "work": "5 2456", "young": "5 13456", "there": "5 2346",
+		"character": "5 16", "through": "5 1456", "where": "5 156", "ought": "5 1256",
+		"upon": "45 136", "word": "45 2456", "these": "45 2346", "those": "45 1456",
+		"whose": "45 156", "cannot": "456 14", "had": "456 125", "many": "456 134",
+		"spirit": "456 234", "world": "456 2456", "their": "456 2346",
+	},
+	map[string]string{
+		"be": "23", "enough": "26", "were": "2356", "his": "236", "in": "35", "was": "356",
+	},
+	[]group{
+		{"and", "12346", anywhere, false}, {"for", "123456", anywhere, false},
+		{"the", "2346", anywhere, false}, {"with", "23456", anywhere, false},
+		{"ing", "346", notFirst, false}, {"of", "12356", anywhere, false},
+		{"ch", "16", anywhere, false}, {"gh", "126", anywhere, false},
+		{"sh", "146", anywhere, false}, {"th", "1456", anywhere, false},
+		{"wh", "156", anywhere, false}, {"ed", "1246", anywhere, false},
+		{"er", "12456", anywhere, false}, {"ou", "1256", anywhere, false},
+		{"ow", "246", anywhere, false}, {"st", "34", anywhere, false},
+		{"ar", "345", anywhere, false},
+		{"en", "26", anywhere, true}, {"in", "35", anywhere, true},
+		{"ea", "2", middle, true}, {"bb", "23", middle, true},
+		{"cc", "25", middle, true}, {"ff", "235", middle, true},
+		{"gg", "2356", middle, true},
+	},
+)
+
+func newContractions(words, lowerWords map[string]string, groups []group) *contractions {
+	c := &contractions{words: map[string]string{}, lowerWords: map[string]string{}}
+	for w, dots := range words {
+		c.words[w] = cells(dots)
+	}
+	for w, dots := range lowerWords {
+		c.lowerWords[w] = cells(dots)
+	}
+	for _, g := range groups {
+		g.braille = cells(g.braille)
+		c.groups = append(c.groups, g)
+	}
+	// Stable, so strong groupsigns stay ahead of lower ones of a length.
+	slices.SortStableFunc(c.groups, func(a, b group) int {
+		return len(b.letters) - len(a.letters)
+	})
+	return c
+}
+
+// apostropheEndings are what may follow a wordsign after an apostrophe,
+// as in "it's", "you're" and "can't", without stopping it from being
+// used.
+var apostropheEndings = map[string]bool{"d": true, "ll": true, "re": true, "s": true, "t": true, "ve": true}
+
+// beforeApostrophe reports where the apostrophe is in the lower-case
+// word w if what comes before it is a wordsign and what comes after is
+// one of apostropheEndings.
+func (c *contractions) beforeApostrophe(w []rune) (int, bool) {
+	k := slices.IndexFunc(w, isApostrophe)
+	if k <= 0 || !apostropheEndings[string(w[k+1:])] {
+		return 0, false
+	}
+	_, ok := c.words[string(w[:k])]
+	return k, ok
+}
+
+// match returns the group to contract at k in the lower-case word w, if
+// any.
+func (c *contractions) match(w []rune, k int) (group, bool) {
+	for _, g := range c.groups {
+		n := len([]rune(g.letters))
+		if k+n > len(w) || string(w[k:k+n]) != g.letters {
+			continue
+		}
+		switch {
+		case g.where == notFirst && k == 0:
+			continue
+		case g.where == middle && (k == 0 || k+n == len(w)):
+			continue
+		case g.lower && c.strongWithin(w, k+1, k+n):
+			continue
+		}
+		return g, true
+	}
+	return group{}, false
+}
+
+// strongWithin reports whether a strong group starts in w[from:to], and
+// so should be contracted rather than a lower group overlapping it: "wear"
+// is w-e-ar, not w-ea-r.
+func (c *contractions) strongWithin(w []rune, from, to int) bool {
+	for k := from; k < to; k++ {
+		for _, g := range c.groups {
+			if !g.lower && strings.HasPrefix(string(w[k:]), g.letters) {
+				return true
+			}
+		}
+	}
+	return false
+}
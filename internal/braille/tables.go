@@ -0,0 +1,123 @@
+package braille
+
+import "strings"
+
+// cells converts dot numbers, one group per cell separated by spaces, to
+// Unicode braille patterns: "45 34" is ⠘⠌.
+func cells(dots string) string {
+	var b strings.Builder
+	for _, cell := range strings.Fields(dots) {
+		r := rune(blank)
+		for _, d := range cell {
+			r |= 1 << (d - '1')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// latin are the letters every table shares.
+var latin = map[rune]string{
+	'a': "1", 'b': "12", 'c': "14", 'd': "145", 'e': "15", 'f': "124", 'g': "1245",
+	'h': "125", 'i': "24", 'j': "245", 'k': "13", 'l': "123", 'm': "134", 'n': "1345",
+	'o': "135", 'p': "1234", 'q': "12345", 'r': "1235", 's': "234", 't': "2345",
+	'u': "136", 'v': "1236", 'w': "2456", 'x': "1346", 'y': "13456", 'z': "1356",
+}
+
+// table is how one language writes Grade 1 braille.
+type table struct {
+	letters map[rune]string // lower case
+	digits  [10]string
+	punct   map[rune]string
+
+	capital string // before a capital letter; twice before a word in capitals
+	numeric string // before a number
+
+	// letterSign separates a number from a following letter a to j, which
+	// would otherwise read as a digit. It is "" where digits don't look
+	// like letters.
+	letterSign string
+
+	grade2 *contractions
+}
+
+func newTable(letters, punct map[rune]string, digits [10]string, capital, numeric, letterSign string) *table {
+	t := &table{
+		letters:    map[rune]string{},
+		punct:      map[rune]string{},
+		capital:    cells(capital),
+		numeric:    cells(numeric),
+		letterSign: cells(letterSign),
+	}
+	for r, dots := range latin {
+		t.letters[r] = cells(dots)
+	}
+	for r, dots := range letters {
+		t.letters[r] = cells(dots)
+	}
+	for r, dots := range punct {
+		t.punct[r] = cells(dots)
+	}
+	for i, dots := range digits {
+		t.digits[i] = cells(dots)
+	}
+	return t
+}
+
+// letterDigits are the digits 0 to 9 written as the letters j and a to i.
+var letterDigits = [10]string{"245", "1", "12", "14", "145", "15", "124", "1245", "125", "24"}
+
+// tables are keyed by BCP 47 language tag.
+var tables = map[string]*table{
+	// Unified English Braille. Accented letters are the letter after a
+	// modifier for the accent, as listed in §4.2 of the Rules of UEB:
+	// grave 45-16, acute 45-34, circumflex 45-146, tilde 45-12456,
+	// diaeresis 45-25, ring 45-1246, cedilla 45-12346 and stroke 4-16.
+	"en": newTable(
+		map[rune]string{
+			'à': "45 16 1", 'á': "45 34 1", 'â': "45 146 1", 'ã': "45 12456 1", 'ä': "45 25 1", 'å': "45 1246 1",
+			'ç': "45 12346 14", 'è': "45 16 15", 'é': "45 34 15", 'ê': "45 146 15", 'ë': "45 25 15",
+			'ì': "45 16 24", 'í': "45 34 24", 'î': "45 146 24", 'ï': "45 25 24", 'ñ': "45 12456 1345",
+			'ò': "45 16 135", 'ó': "45 34 135", 'ô': "45 146 135", 'õ': "45 12456 135", 'ö': "45 25 135",
+			'ø': "4 16 135", 'ù': "45 16 136", 'ú': "45 34 136", 'û': "45 146 136", 'ü': "45 25 136",
+			'ý': "45 34 13456", 'ÿ': "45 25 13456",
+		},
+		map[rune]string{
+			',': "2", '.': "256", '!': "235", '?': "236", '\'': "3", '’': "3",
+			'-': "36", ':': "25", ';': "23",
+		},
+		letterDigits, "6", "3456", "56",
+	),
+
+	// Spanish, as in ONCE's Código Braille Español.
+	"es": newTable(
+		map[rune]string{
+			'á': "12356", 'é': "2346", 'í': "34", 'ó': "346", 'ú': "23456", 'ü': "1256", 'ñ': "12456",
+		},
+		map[rune]string{
+			',': "2", '.': "3", '!': "235", '¡': "235", '?': "26", '¿': "26",
+			'\'': "3", '’': "3", '-': "36", ':': "25", ';': "23",
+		},
+		letterDigits, "46", "3456", "5",
+	),
+
+	// French, as in the Code braille français uniformisé. Digits are
+	// Antoine's: the letters a to j with dot 6, so they need no letter
+	// sign.
+	"fr": newTable(
+		map[rune]string{
+			'à': "12356", 'â': "16", 'ç': "12346", 'è': "2346", 'é': "123456", 'ê': "126", 'ë': "1246",
+			'î': "146", 'ï': "12456", 'ô': "1456", 'œ': "246", 'ù': "23456", 'û': "156", 'ü': "1256",
+		},
+		map[rune]string{
+			',': "2", '.': "256", '!': "235", '?': "26", '\'': "3", '’': "3",
+			'-': "36", ':': "25", ';': "23",
+		},
+		[10]string{"3456", "16", "126", "146", "1456", "156", "1246", "12456", "1256", "246"},
+		"46", "6", "",
+	),
+}
+
+func init() {
+	tables["en"].grade2 = englishContractions
+}
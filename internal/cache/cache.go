@@ -0,0 +1,258 @@
+// Package cache is an in-memory LRU cache with expiry, split into shards
+// so that goroutines working on different keys rarely wait for each
+// other. Concurrent misses on one key share a single load.
+package cache
+
+import (
+	"container/list"
+	"errors"
+	"hash/maphash"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Options configure a Cache.
+type Options struct {
+	// MaxEntries bounds the number of entries, split evenly between the
+	// shards. Default 10,000.
+	MaxEntries int
+	// Shards is the number of independently locked parts. Default 16.
+	Shards int
+	// TTL is how long an entry stays fresh. Zero keeps entries until
+	// they are evicted.
+	TTL time.Duration
+	// Now is the clock, time.Now by default.
+	Now func() time.Time
+}
+
+// Stats count what a Cache did since it was created.
+type Stats struct {
+	Hits        uint64
+	Misses      uint64
+	Loads       uint64 // load calls made by GetOrLoad
+	Shared      uint64 // misses that waited for another goroutine's load
+	Evictions   uint64 // entries dropped to make room
+	Expirations uint64 // entries dropped for being older than the TTL
+	Entries     int
+}
+
+// HitRate is the share of lookups that were hits.
+func (s Stats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// Cache maps keys to values. It is safe for concurrent use.
+type Cache[K comparable, V any] struct {
+	shards []*shard[K, V]
+	seed   maphash.Seed
+	ttl    time.Duration
+	now    func() time.Time
+
+	// generation counts Purges, so a load that started before one
+	// doesn't store what may now be a stale value.
+	generation atomic.Uint64
+
+	hits, misses, loads, shared, evictions, expirations atomic.Uint64
+}
+
+type shard[K comparable, V any] struct {
+	mu    sync.Mutex
+	max   int
+	items map[K]*list.Element // of *entry
+	lru   *list.List          // most recently used first
+	calls map[K]*call[V]
+}
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+}
+
+// call is a load in progress.
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// New returns an empty Cache.
+func New[K comparable, V any](opts Options) *Cache[K, V] {
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = 10_000
+	}
+	if opts.Shards <= 0 {
+		opts.Shards = 16
+	}
+	opts.Shards = min(opts.Shards, opts.MaxEntries)
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	c := &Cache[K, V]{seed: maphash.MakeSeed(), ttl: opts.TTL, now: opts.Now}
+	perShard := (opts.MaxEntries + opts.Shards - 1) / opts.Shards
+	for range opts.Shards {
+		c.shards = append(c.shards, &shard[K, V]{
+			max:   perShard,
+			items: map[K]*list.Element{},
+			lru:   list.New(),
+			calls: map[K]*call[V]{},
+		})
+	}
+	return c
+}
+
+func (c *Cache[K, V]) shard(key K) *shard[K, V] {
+	return c.shards[maphash.Comparable(c.seed, key)%uint64(len(c.shards))]
+}
+
+// Get returns the value for key, if it has a fresh one.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	s := c.shard(key)
+	s.mu.Lock()
+	v, ok := c.lookup(s, key)
+	s.mu.Unlock()
+	c.count(ok)
+	return v, ok
+}
+
+func (c *Cache[K, V]) count(hit bool) {
+	if hit {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+}
+
+// lookup finds key in s, which the caller has locked, and marks it as
+// recently used. An expired entry is removed.
+func (c *Cache[K, V]) lookup(s *shard[K, V], key K) (V, bool) {
+	var zero V
+	el, ok := s.items[key]
+	if !ok {
+		return zero, false
+	}
+	e := el.Value.(*entry[K, V])
+	if c.ttl > 0 && !c.now().Before(e.expires) {
+		s.lru.Remove(el)
+		delete(s.items, key)
+		c.expirations.Add(1)
+		return zero, false
+	}
+	s.lru.MoveToFront(el)
+	return e.value, true
+}
+
+// Set stores value for key, evicting the least recently used entry of
+// its shard if the shard is full.
+func (c *Cache[K, V]) Set(key K, value V) {
+	s := c.shard(key)
+	s.mu.Lock()
+	c.store(s, key, value)
+	s.mu.Unlock()
+}
+
+func (c *Cache[K, V]) store(s *shard[K, V], key K, value V) {
+	var expires time.Time
+	if c.ttl > 0 {
+		expires = c.now().Add(c.ttl)
+	}
+	if el, ok := s.items[key]; ok {
+		e := el.Value.(*entry[K, V])
+		e.value, e.expires = value, expires
+		s.lru.MoveToFront(el)
+		return
+	}
+	s.items[key] = s.lru.PushFront(&entry[K, V]{key, value, expires})
+	if s.lru.Len() > s.max {
+		oldest := s.lru.Back()
+		s.lru.Remove(oldest)
+		delete(s.items, oldest.Value.(*entry[K, V]).key)
+		c.evictions.Add(1)
+	}
+}
+
+var errLoadPanicked = errors.New("cache: load panicked")
+
+// GetOrLoad returns the value for key, calling load to get it on a miss.
+// Goroutines missing on the same key at once share one call of load.
+// Errors are returned to every waiting caller but not cached.
+func (c *Cache[K, V]) GetOrLoad(key K, load func() (V, error)) (V, error) {
+	s := c.shard(key)
+	s.mu.Lock()
+	if v, ok := c.lookup(s, key); ok {
+		s.mu.Unlock()
+		c.count(true)
+		return v, nil
+	}
+	c.count(false)
+	if cl, ok := s.calls[key]; ok {
+		s.mu.Unlock()
+		c.shared.Add(1)
+		<-cl.done
+		return cl.value, cl.err
+	}
+	cl := &call[V]{done: make(chan struct{})}
+	s.calls[key] = cl
+	generation := c.generation.Load()
+	s.mu.Unlock()
+
+	c.loads.Add(1)
+	defer func() {
+		// Runs even if load panics, so waiting goroutines aren't stuck.
+		s.mu.Lock()
+		if cl.err == nil && c.generation.Load() == generation {
+			c.store(s, key, cl.value)
+		}
+		delete(s.calls, key)
+		s.mu.Unlock()
+		close(cl.done)
+	}()
+	cl.err = errLoadPanicked
+	cl.value, cl.err = load()
+	return cl.value, cl.err
+}
+
+// Delete removes key.
+func (c *Cache[K, V]) Delete(key K) {
+	s := c.shard(key)
+	s.mu.Lock()
+	if el, ok := s.items[key]; ok {
+		s.lru.Remove(el)
+		delete(s.items, key)
+	}
+	s.mu.Unlock()
+}
+
+// Purge removes every entry, for when what the values were computed from
+// has changed. Loads in progress return their value but don't store it.
+func (c *Cache[K, V]) Purge() {
+	c.generation.Add(1)
+	for _, s := range c.shards {
+		s.mu.Lock()
+		clear(s.items)
+		s.lru.Init()
+		s.mu.Unlock()
+	}
+}
+
+// Stats returns the cache's counters and current size.
+func (c *Cache[K, V]) Stats() Stats {
+	st := Stats{
+		Hits:        c.hits.Load(),
+		Misses:      c.misses.Load(),
+		Loads:       c.loads.Load(),
+		Shared:      c.shared.Load(),
+		Evictions:   c.evictions.Load(),
+		Expirations: c.expirations.Load(),
+	}
+	for _, s := range c.shards {
+		s.mu.Lock()
+		st.Entries += s.lru.Len()
+		s.mu.Unlock()
+	}
+	return st
+}
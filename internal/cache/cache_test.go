@@ -0,0 +1,192 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// clock is a fake time.Now.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func assertGet(t testing.TB, c *Cache[string, int], key string, want int, wantOK bool) {
+	t.Helper()
+	got, ok := c.Get(key)
+	if got != want || ok != wantOK {
+		t.Errorf("Get(%q): got %d, %v want %d, %v", key, got, ok, want, wantOK)
+	}
+}
+
+func TestLRU(t *testing.T) {
+	c := New[string, int](Options{MaxEntries: 3, Shards: 1})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	c.Get("a") // a is now the most recently used
+	c.Set("d", 4)
+
+	assertGet(t, c, "b", 0, false)
+	assertGet(t, c, "a", 1, true)
+	assertGet(t, c, "d", 4, true)
+
+	c.Set("a", 10)
+	c.Delete("d")
+	assertGet(t, c, "a", 10, true)
+	assertGet(t, c, "d", 0, false)
+
+	st := c.Stats()
+	if st.Evictions != 1 || st.Entries != 2 || st.Hits != 4 || st.Misses != 2 {
+		t.Errorf("got %+v", st)
+	}
+}
+
+func TestShardsBound(t *testing.T) {
+	c := New[int, int](Options{MaxEntries: 100, Shards: 8})
+	for i := range 1000 {
+		c.Set(i, i)
+	}
+	// Each shard holds ceil(100/8) = 13, so at most 104 in all.
+	if n := c.Stats().Entries; n > 104 || n < 90 {
+		t.Errorf("got %d entries", n)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	clk := &clock{t: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	c := New[string, int](Options{TTL: time.Minute, Now: clk.now})
+	c.Set("a", 1)
+
+	clk.advance(59 * time.Second)
+	assertGet(t, c, "a", 1, true)
+	clk.advance(time.Second)
+	assertGet(t, c, "a", 0, false)
+
+	if st := c.Stats(); st.Expirations != 1 || st.Entries != 0 {
+		t.Errorf("got %+v", st)
+	}
+}
+
+func TestGetOrLoad(t *testing.T) {
+	c := New[string, int](Options{})
+
+	t.Run("concurrent misses share a load", func(t *testing.T) {
+		release := make(chan struct{})
+		var calls atomic.Int32
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				v, err := c.GetOrLoad("slow", func() (int, error) {
+					calls.Add(1)
+					<-release
+					return 42, nil
+				})
+				if v != 42 || err != nil {
+					t.Errorf("got %d, %v", v, err)
+				}
+			}()
+		}
+		// Let every goroutine miss before the load finishes.
+		for c.Stats().Misses < 10 {
+			time.Sleep(time.Millisecond)
+		}
+		close(release)
+		wg.Wait()
+
+		if calls.Load() != 1 {
+			t.Errorf("load called %d times", calls.Load())
+		}
+		if st := c.Stats(); st.Loads != 1 || st.Shared != 9 {
+			t.Errorf("got %+v", st)
+		}
+		assertGet(t, c, "slow", 42, true)
+	})
+
+	t.Run("errors aren't cached", func(t *testing.T) {
+		boom := errors.New("boom")
+		if _, err := c.GetOrLoad("bad", func() (int, error) { return 0, boom }); err != boom {
+			t.Errorf("got %v", err)
+		}
+		v, err := c.GetOrLoad("bad", func() (int, error) { return 7, nil })
+		if v != 7 || err != nil {
+			t.Errorf("got %d, %v", v, err)
+		}
+	})
+
+	t.Run("panics release waiters", func(t *testing.T) {
+		func() {
+			defer func() { recover() }()
+			c.GetOrLoad("panic", func() (int, error) { panic("oops") })
+		}()
+		v, err := c.GetOrLoad("panic", func() (int, error) { return 1, nil })
+		if v != 1 || err != nil {
+			t.Errorf("got %d, %v", v, err)
+		}
+	})
+}
+
+func TestPurge(t *testing.T) {
+	c := New[string, int](Options{})
+	c.Set("a", 1)
+
+	// A load that started before the purge must not store its value.
+	started, release := make(chan struct{}), make(chan struct{})
+	done := make(chan int)
+	go func() {
+		v, _ := c.GetOrLoad("b", func() (int, error) {
+			close(started)
+			<-release
+			return 2, nil
+		})
+		done <- v
+	}()
+	<-started
+	c.Purge()
+	close(release)
+
+	if v := <-done; v != 2 {
+		t.Errorf("load returned %d", v)
+	}
+	assertGet(t, c, "a", 0, false)
+	assertGet(t, c, "b", 0, false)
+}
+
+// BenchmarkGet shows how lookups contend for locks: run it with
+// -cpu=1,4,16 and compare one shard with many.
+func BenchmarkGet(b *testing.B) {
+	for _, shards := range []int{1, 16} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			c := New[int, int](Options{MaxEntries: 4096, Shards: shards})
+			for i := range 4096 {
+				c.Set(i, i)
+			}
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					c.Get(i & 4095)
+					i++
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkGetOrLoad(b *testing.B) {
+	c := New[int, int](Options{MaxEntries: 1024})
+	load := func() (int, error) { return 1, nil }
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			// Four times more keys than room: a mix of hits and misses.
+			c.GetOrLoad(i&4095, load)
+			i += 7
+		}
+	})
+}
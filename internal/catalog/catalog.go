@@ -0,0 +1,92 @@
+// Package catalog stores greeting prefixes by language: in a map, in a
+// local kv store, or in SQL tables reached through database/sql. The SQL
+// schema is versioned: Open migrates a database to the latest version
+// before using it. The statements are plain PostgreSQL, where the
+// production catalog lives.
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Map is a fixed catalog of language names to prefixes.
+type Map map[string]string
+
+// Prefix returns the prefix for language and whether there is one.
+func (m Map) Prefix(ctx context.Context, language string) (string, bool, error) {
+	p, ok := m[language]
+	return p, ok, nil
+}
+
+// SQL is a catalog in the greetings table of a database.
+type SQL struct {
+	db *sql.DB
+}
+
+// Open migrates db to the latest schema and returns a catalog using it.
+func Open(ctx context.Context, db *sql.DB) (*SQL, error) {
+	if _, err := Migrate(ctx, db); err != nil {
+		return nil, err
+	}
+	return &SQL{db: db}, nil
+}
+
+// Prefix returns the prefix for language and whether there is one.
+func (s *SQL) Prefix(ctx context.Context, language string) (string, bool, error) {
+	var prefix string
+	err := s.db.QueryRowContext(ctx, `SELECT prefix FROM greetings WHERE language = $1`, language).Scan(&prefix)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("catalog: %w", err)
+	}
+	return prefix, true, nil
+}
+
+// Put adds language or changes its prefix.
+func (s *SQL) Put(ctx context.Context, language, prefix string) error {
+	if language == "" || prefix == "" {
+		return errors.New("catalog: language and prefix can't be empty")
+	}
+	_, err := s.db.ExecContext(ctx, `INSERT INTO greetings (language, prefix) VALUES ($1, $2)
+		ON CONFLICT (language) DO UPDATE SET prefix = EXCLUDED.prefix`, language, prefix)
+	if err != nil {
+		return fmt.Errorf("catalog: %w", err)
+	}
+	return nil
+}
+
+// Delete removes language. Removing a language that isn't there is not an
+// error.
+func (s *SQL) Delete(ctx context.Context, language string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM greetings WHERE language = $1`, language); err != nil {
+		return fmt.Errorf("catalog: %w", err)
+	}
+	return nil
+}
+
+// Languages returns every language in the catalog, sorted.
+func (s *SQL) Languages(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT language FROM greetings ORDER BY language`)
+	if err != nil {
+		return nil, fmt.Errorf("catalog: %w", err)
+	}
+	defer rows.Close()
+
+	var languages []string
+	for rows.Next() {
+		var l string
+		if err := rows.Scan(&l); err != nil {
+			return nil, fmt.Errorf("catalog: %w", err)
+		}
+		languages = append(languages, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("catalog: %w", err)
+	}
+	return languages, nil
+}
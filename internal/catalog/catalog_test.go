@@ -0,0 +1,210 @@
+package catalog
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"learn-go/internal/kv"
+)
+
+var ctx = context.Background()
+
+func TestMigrate(t *testing.T) {
+	db, fake := openFake(t)
+
+	v, err := Migrate(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != 2 {
+		t.Errorf("migrated to version %d want 2", v)
+	}
+	// Migrating again changes nothing: the seed would fail as duplicates.
+	if v, err = Migrate(ctx, db); err != nil || v != 2 {
+		t.Errorf("second migration got version %d, %v\n%s", v, err, fake.dump())
+	}
+	if v, err := SchemaVersion(ctx, db); err != nil || v != 2 {
+		t.Errorf("got schema version %d, %v", v, err)
+	}
+
+	t.Run("newer database", func(t *testing.T) {
+		if _, err := db.ExecContext(ctx, `UPDATE schema_version SET version = $1`, 3); err != nil {
+			t.Fatal(err)
+		}
+		_, err := Migrate(ctx, db)
+		assertError(t, err, "database schema is version 3, newer than this program's 2")
+	})
+}
+
+func TestMigrateConcurrent(t *testing.T) {
+	db, fake := openFake(t)
+	fake.latency = time.Millisecond
+
+	const migrators = 8
+	var wg sync.WaitGroup
+	for i := 0; i < migrators; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := Migrate(ctx, db); err != nil || v != 2 {
+				t.Errorf("got version %d, %v", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Each migration was committed once.
+	for _, m := range []string{"CREATE TABLE greetings", "INSERT INTO greetings"} {
+		n := 0
+		for _, stmt := range fake.committed {
+			if strings.HasPrefix(stmt, m) {
+				n++
+			}
+		}
+		if n != 1 {
+			t.Errorf("%q committed %d times, want once", m, n)
+		}
+	}
+}
+
+func TestMigrateFailure(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/0001_one.sql":   {Data: []byte("CREATE TABLE one (id INTEGER);")},
+		"m/0002_two.sql":   {Data: []byte("CREATE TABLE two (id INTEGER);\nINSERT INTO two (id) VALUES (1);")},
+		"m/0003_three.sql": {Data: []byte("CREATE TABLE three (id INTEGER);")},
+	}
+	ms, err := loadMigrations(fsys, "m")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, fake := openFake(t)
+	fake.failOn = "INSERT INTO two"
+	v, err := migrate(ctx, db, ms)
+	assertError(t, err, "migration 0002_two: fakedb: injected failure")
+	if v != 1 {
+		t.Errorf("stopped at version %d want 1", v)
+	}
+	// The failed migration is rolled back whole.
+	if _, ok := fake.tables["two"]; ok {
+		t.Errorf("table two was left behind:\n%s", fake.dump())
+	}
+
+	fake.failOn = ""
+	if v, err := migrate(ctx, db, ms); err != nil || v != 3 {
+		t.Errorf("retry got version %d, %v", v, err)
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	ms, err := Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) != 2 || ms[0].Name != "create_greetings" || len(ms[1].Statements) != 1 {
+		t.Errorf("got %+v", ms)
+	}
+
+	bad := []struct {
+		name string
+		fsys fstest.MapFS
+		want string
+	}{
+		{"gap", fstest.MapFS{"m/0001_a.sql": {}, "m/0003_c.sql": {}}, "migration 0003_c: want version 2"},
+		{"bad name", fstest.MapFS{"m/1_a.sql": {}}, "migration 1_a.sql: want a name like 0001_create_greetings.sql"},
+	}
+	for _, c := range bad {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := loadMigrations(c.fsys, "m")
+			assertError(t, err, c.want)
+		})
+	}
+}
+
+func TestSQL(t *testing.T) {
+	db, _ := openFake(t)
+	c, err := Open(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertPrefix(t, c, "Spanish", "Hola, ", true)
+	assertPrefix(t, c, "Klingon", "", false)
+
+	if err := c.Put(ctx, "Klingon", "nuqneH, "); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Put(ctx, "Spanish", "¡Hola, "); err != nil {
+		t.Fatal(err)
+	}
+	assertPrefix(t, c, "Klingon", "nuqneH, ", true)
+	assertPrefix(t, c, "Spanish", "¡Hola, ", true)
+	assertError(t, c.Put(ctx, "", "Hi, "), "catalog: language and prefix can't be empty")
+
+	if err := c.Delete(ctx, "French"); err != nil {
+		t.Fatal(err)
+	}
+	assertPrefix(t, c, "French", "", false)
+
+	languages, err := c.Languages(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(languages, ","); got != "English,Klingon,Spanish" {
+		t.Errorf("got languages %s", got)
+	}
+}
+
+func TestKV(t *testing.T) {
+	db, err := kv.Open(filepath.Join(t.TempDir(), "greetings.kv"), kv.Options{NoSync: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.Put("lang/max", []byte("English"))
+	c := NewKV(db)
+
+	c.Put(ctx, "Spanish", "Hola, ")
+	c.Put(ctx, "Klingon", "nuqneH, ")
+	c.Delete(ctx, "Klingon")
+	assertPrefix(t, c, "Spanish", "Hola, ", true)
+	assertPrefix(t, c, "Klingon", "", false)
+
+	languages, err := c.Languages(ctx)
+	if err != nil || strings.Join(languages, ",") != "Spanish" {
+		t.Errorf("got languages %q, %v", languages, err)
+	}
+}
+
+func TestMap(t *testing.T) {
+	m := Map{"English": "Hello, "}
+	assertPrefix(t, m, "English", "Hello, ", true)
+	assertPrefix(t, m, "French", "", false)
+}
+
+type prefixer interface {
+	Prefix(ctx context.Context, language string) (string, bool, error)
+}
+
+func assertPrefix(t testing.TB, c prefixer, language, want string, wantOK bool) {
+	t.Helper()
+	got, ok, err := c.Prefix(ctx, language)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want || ok != wantOK {
+		t.Errorf("%s: got %q, %v want %q, %v", language, got, ok, want, wantOK)
+	}
+}
+
+func assertError(t testing.TB, err error, want string) {
+	t.Helper()
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("got error %v want %q", err, want)
+	}
+}
@@ -0,0 +1,400 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeDB is an in-memory database understanding just the SQL this
+// package sends, so the tests need no database server.
+type fakeDB struct {
+	mu     sync.Mutex
+	tables map[string]*table
+	// advisory is PostgreSQL's advisory lock, for the transaction holding
+	// it.
+	advisory sync.Mutex
+	// failOn makes statements containing it fail, to test errors.
+	failOn string
+	// latency is how long each statement takes, to make transactions
+	// overlap.
+	latency time.Duration
+	// committed are the statements of every committed transaction.
+	committed []string
+}
+
+type table struct {
+	cols []string
+	key  string // primary key column, if any
+	rows [][]driver.Value
+}
+
+func (t *table) clone() *table {
+	c := &table{cols: t.cols, key: t.key}
+	for _, r := range t.rows {
+		c.rows = append(c.rows, slices.Clone(r))
+	}
+	return c
+}
+
+func (t *table) col(name string) (int, error) {
+	if i := slices.Index(t.cols, name); i >= 0 {
+		return i, nil
+	}
+	return 0, fmt.Errorf("column %q does not exist", name)
+}
+
+// openFake returns a database backed by a new fakeDB.
+func openFake(t testing.TB) (*sql.DB, *fakeDB) {
+	f := &fakeDB{tables: map[string]*table{}}
+	db := sql.OpenDB(f)
+	t.Cleanup(func() { db.Close() })
+	return db, f
+}
+
+func (f *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: f}, nil }
+func (f *fakeDB) Driver() driver.Driver                        { return f }
+func (f *fakeDB) Open(string) (driver.Conn, error)             { return &fakeConn{db: f}, nil }
+
+type fakeConn struct {
+	db     *fakeDB
+	tx     map[string]*table // the transaction's copy of the tables
+	stmts  []string          // the transaction's statements
+	locked bool              // holds db.advisory
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{c, query}, nil }
+func (c *fakeConn) Close() error                              { return nil }
+
+// Begin starts a transaction on a copy of the tables, which Commit puts
+// in their place. Transactions aren't isolated from each other any
+// further: the last to commit wins, unless they take the advisory lock.
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.snapshot()
+	return c, nil
+}
+
+func (c *fakeConn) snapshot() {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.tx = map[string]*table{}
+	for name, t := range c.db.tables {
+		c.tx[name] = t.clone()
+	}
+}
+
+func (c *fakeConn) Commit() error {
+	c.db.mu.Lock()
+	c.db.tables = c.tx
+	c.db.committed = append(c.db.committed, c.stmts...)
+	c.db.mu.Unlock()
+	return c.end()
+}
+
+func (c *fakeConn) Rollback() error {
+	return c.end()
+}
+
+func (c *fakeConn) end() error {
+	c.tx, c.stmts = nil, nil
+	if c.locked {
+		c.locked = false
+		c.db.advisory.Unlock()
+	}
+	return nil
+}
+
+type fakeStmt struct {
+	c     *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	_, n, err := s.c.run(s.query, args)
+	return driver.RowsAffected(n), err
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	rows, _, err := s.c.run(s.query, args)
+	return rows, err
+}
+
+type fakeRows struct {
+	cols []string
+	rows [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.cols }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+var (
+	createRe = regexp.MustCompile(`^CREATE TABLE (IF NOT EXISTS )?(\w+) \((.*)\)$`)
+	insertRe = regexp.MustCompile(`^INSERT INTO (\w+) \(([\w, ]+)\) VALUES (.*?)( ON CONFLICT \((\w+)\) DO UPDATE SET (\w+) = EXCLUDED\.(\w+))?$`)
+	selectRe = regexp.MustCompile(`^SELECT ([\w, ]+) FROM (\w+)( WHERE (\w+) = (\S+))?( ORDER BY (\w+))?( FOR UPDATE)?$`)
+	updateRe = regexp.MustCompile(`^UPDATE (\w+) SET (\w+) = (\S+)$`)
+	deleteRe = regexp.MustCompile(`^DELETE FROM (\w+) WHERE (\w+) = (\S+)$`)
+	tupleRe  = regexp.MustCompile(`\(([^()]*)\)`)
+	lockRe   = regexp.MustCompile(`^SELECT pg_advisory_xact_lock\(\S+\)$`)
+)
+
+// run runs one statement and returns its rows and how many it changed.
+func (c *fakeConn) run(query string, args []driver.Value) (*fakeRows, int64, error) {
+	q := strings.Join(strings.Fields(query), " ")
+	time.Sleep(c.db.latency)
+	if c.tx != nil {
+		c.stmts = append(c.stmts, q)
+	}
+	if c.db.failOn != "" && strings.Contains(q, c.db.failOn) {
+		return nil, 0, errors.New("fakedb: injected failure")
+	}
+
+	if lockRe.MatchString(q) {
+		if c.tx == nil {
+			return nil, 0, errors.New("fakedb: advisory lock outside a transaction")
+		}
+		c.db.advisory.Lock()
+		c.locked = true
+		// Later statements see what was committed while waiting.
+		c.snapshot()
+		return &fakeRows{cols: []string{"pg_advisory_xact_lock"}, rows: [][]driver.Value{{nil}}}, 0, nil
+	}
+
+	tables := c.tx
+	if tables == nil {
+		c.db.mu.Lock()
+		defer c.db.mu.Unlock()
+		tables = c.db.tables
+	}
+	get := func(name string) (*table, error) {
+		if t, ok := tables[name]; ok {
+			return t, nil
+		}
+		return nil, fmt.Errorf("relation %q does not exist", name)
+	}
+
+	switch {
+	case createRe.MatchString(q):
+		m := createRe.FindStringSubmatch(q)
+		if _, ok := tables[m[2]]; ok {
+			if m[1] != "" {
+				return nil, 0, nil
+			}
+			return nil, 0, fmt.Errorf("relation %q already exists", m[2])
+		}
+		t := &table{}
+		for _, def := range strings.Split(m[3], ",") {
+			f := strings.Fields(def)
+			t.cols = append(t.cols, f[0])
+			if strings.Contains(def, "PRIMARY KEY") {
+				t.key = f[0]
+			}
+		}
+		tables[m[2]] = t
+		return nil, 0, nil
+
+	case insertRe.MatchString(q):
+		m := insertRe.FindStringSubmatch(q)
+		t, err := get(m[1])
+		if err != nil {
+			return nil, 0, err
+		}
+		cols := splitList(m[2])
+		var n int64
+		for _, tuple := range tupleRe.FindAllStringSubmatch(m[3], -1) {
+			row := make([]driver.Value, len(t.cols))
+			for i, v := range splitList(tuple[1]) {
+				ci, err := t.col(cols[i])
+				if err != nil {
+					return nil, 0, err
+				}
+				if row[ci], err = value(v, args); err != nil {
+					return nil, 0, err
+				}
+			}
+			if existing := t.find(row); existing != nil {
+				if m[4] == "" {
+					return nil, 0, fmt.Errorf("duplicate key value violates unique constraint on %s", t.key)
+				}
+				set, _ := t.col(m[6])
+				from, _ := t.col(m[7])
+				existing[set] = row[from]
+			} else {
+				t.rows = append(t.rows, row)
+			}
+			n++
+		}
+		return nil, n, nil
+
+	case selectRe.MatchString(q):
+		m := selectRe.FindStringSubmatch(q)
+		t, err := get(m[2])
+		if err != nil {
+			return nil, 0, err
+		}
+		rows, err := t.where(m[4], m[5], args)
+		if err != nil {
+			return nil, 0, err
+		}
+		if m[7] != "" {
+			oi, err := t.col(m[7])
+			if err != nil {
+				return nil, 0, err
+			}
+			slices.SortFunc(rows, func(a, b []driver.Value) int { return strings.Compare(fmt.Sprint(a[oi]), fmt.Sprint(b[oi])) })
+		}
+		out := &fakeRows{cols: splitList(m[1])}
+		for _, r := range rows {
+			var o []driver.Value
+			for _, col := range out.cols {
+				ci, err := t.col(col)
+				if err != nil {
+					return nil, 0, err
+				}
+				o = append(o, r[ci])
+			}
+			out.rows = append(out.rows, o)
+		}
+		return out, 0, nil
+
+	case updateRe.MatchString(q):
+		m := updateRe.FindStringSubmatch(q)
+		t, err := get(m[1])
+		if err != nil {
+			return nil, 0, err
+		}
+		ci, err := t.col(m[2])
+		if err != nil {
+			return nil, 0, err
+		}
+		v, err := value(m[3], args)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, r := range t.rows {
+			r[ci] = v
+		}
+		return nil, int64(len(t.rows)), nil
+
+	case deleteRe.MatchString(q):
+		m := deleteRe.FindStringSubmatch(q)
+		t, err := get(m[1])
+		if err != nil {
+			return nil, 0, err
+		}
+		gone, err := t.where(m[2], m[3], args)
+		if err != nil {
+			return nil, 0, err
+		}
+		t.rows = slices.DeleteFunc(t.rows, func(r []driver.Value) bool {
+			return slices.ContainsFunc(gone, func(g []driver.Value) bool { return &g[0] == &r[0] })
+		})
+		return nil, int64(len(gone)), nil
+	}
+	return nil, 0, fmt.Errorf("fakedb: unsupported statement %q", q)
+}
+
+// find returns the row with row's primary key.
+func (t *table) find(row []driver.Value) []driver.Value {
+	if t.key == "" {
+		return nil
+	}
+	ki, _ := t.col(t.key)
+	for _, r := range t.rows {
+		if r[ki] == row[ki] {
+			return r
+		}
+	}
+	return nil
+}
+
+// where returns the rows whose col equals the value v, or every row if
+// col is empty.
+func (t *table) where(col, v string, args []driver.Value) ([][]driver.Value, error) {
+	if col == "" {
+		return slices.Clone(t.rows), nil
+	}
+	ci, err := t.col(col)
+	if err != nil {
+		return nil, err
+	}
+	want, err := value(v, args)
+	if err != nil {
+		return nil, err
+	}
+	var rows [][]driver.Value
+	for _, r := range t.rows {
+		if r[ci] == want {
+			rows = append(rows, r)
+		}
+	}
+	return rows, nil
+}
+
+// value evaluates a placeholder like $1, a 'string' or a number.
+func value(v string, args []driver.Value) (driver.Value, error) {
+	switch {
+	case strings.HasPrefix(v, "$"):
+		n, err := strconv.Atoi(v[1:])
+		if err != nil || n < 1 || n > len(args) {
+			return nil, fmt.Errorf("bad placeholder %s", v)
+		}
+		return args[n-1], nil
+	case strings.HasPrefix(v, "'"):
+		return strings.ReplaceAll(strings.Trim(v, "'"), "''", "'"), nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	return n, err
+}
+
+// splitList splits "a, 'b, c', d" at the commas outside quotes.
+func splitList(s string) []string {
+	var (
+		items  []string
+		quoted bool
+		start  int
+	)
+	for i, r := range s {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			items = append(items, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(items, strings.TrimSpace(s[start:]))
+}
+
+// dump lists the tables for error messages.
+func (f *fakeDB) dump() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var b strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(f.tables)) {
+		fmt.Fprintf(&b, "%s %v\n", name, f.tables[name].rows)
+	}
+	return b.String()
+}
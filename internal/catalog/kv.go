@@ -0,0 +1,56 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"learn-go/internal/kv"
+)
+
+// kvPrefix is where KV keeps its prefixes, so the store can hold other
+// greeting state too.
+const kvPrefix = "prefix/"
+
+// KV is a catalog in a local kv store, under keys like "prefix/Spanish".
+type KV struct {
+	db *kv.DB
+}
+
+// NewKV returns a catalog using db.
+func NewKV(db *kv.DB) *KV {
+	return &KV{db: db}
+}
+
+// Prefix returns the prefix for language and whether there is one.
+func (c *KV) Prefix(ctx context.Context, language string) (string, bool, error) {
+	p, ok, err := c.db.Get(kvPrefix + language)
+	if err != nil {
+		return "", false, fmt.Errorf("catalog: %w", err)
+	}
+	return string(p), ok, nil
+}
+
+// Put adds language or changes its prefix.
+func (c *KV) Put(ctx context.Context, language, prefix string) error {
+	if language == "" || prefix == "" {
+		return errors.New("catalog: language and prefix can't be empty")
+	}
+	return c.db.Put(kvPrefix+language, []byte(prefix))
+}
+
+// Delete removes language.
+func (c *KV) Delete(ctx context.Context, language string) error {
+	return c.db.Delete(kvPrefix + language)
+}
+
+// Languages returns every language in the catalog, sorted.
+func (c *KV) Languages(ctx context.Context) ([]string, error) {
+	var languages []string
+	err := c.db.Scan(kvPrefix, func(key string, _ []byte) error {
+		languages = append(languages, strings.TrimPrefix(key, kvPrefix))
+		return nil
+	})
+	return languages, err
+}
@@ -0,0 +1,166 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migration is one step of the schema, read from migrations/NNNN_name.sql.
+type Migration struct {
+	Version    int
+	Name       string
+	Statements []string
+}
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var migrationName = regexp.MustCompile(`^(\d{4})_([a-z0-9_]+)\.sql$`)
+
+// Migrations returns the schema's migrations in order. Versions start at
+// 1 and have no gaps, so the schema version is the number applied.
+func Migrations() ([]Migration, error) {
+	return loadMigrations(migrationFiles, "migrations")
+}
+
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	var ms []Migration
+	for _, e := range entries {
+		m := migrationName.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("migration %s: want a name like 0001_create_greetings.sql", e.Name())
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		v, _ := strconv.Atoi(m[1])
+		ms = append(ms, Migration{Version: v, Name: m[2], Statements: splitStatements(string(data))})
+	}
+	sort.Slice(ms, func(i, j int) bool { return ms[i].Version < ms[j].Version })
+	for i, m := range ms {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("migration %04d_%s: want version %d", m.Version, m.Name, i+1)
+		}
+	}
+	return ms, nil
+}
+
+// splitStatements drops -- comments and splits src at the semicolons
+// that end its statements. Migrations don't put semicolons in strings.
+func splitStatements(src string) []string {
+	var lines []string
+	for _, line := range strings.Split(src, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "--") {
+			lines = append(lines, line)
+		}
+	}
+	var stmts []string
+	for _, s := range strings.Split(strings.Join(lines, "\n"), ";") {
+		if s = strings.TrimSpace(s); s != "" {
+			stmts = append(stmts, s)
+		}
+	}
+	return stmts
+}
+
+// SchemaVersion returns the number of migrations applied to db. It
+// fails on a database Migrate never ran on.
+func SchemaVersion(ctx context.Context, db *sql.DB) (int, error) {
+	var v int
+	if err := db.QueryRowContext(ctx, `SELECT version FROM schema_version`).Scan(&v); err != nil {
+		return 0, fmt.Errorf("catalog: schema version: %w", err)
+	}
+	return v, nil
+}
+
+// Migrate applies the migrations db hasn't had yet, each in its own
+// transaction, and returns the schema version it ends at. Processes
+// migrating the same database at once take turns, so each migration is
+// applied once. A database
+// migrated by a newer version of this package is left alone and is an
+// error: its schema may not be what this one expects.
+func Migrate(ctx context.Context, db *sql.DB) (int, error) {
+	ms, err := Migrations()
+	if err != nil {
+		return 0, err
+	}
+	return migrate(ctx, db, ms)
+}
+
+// migrationLock is the PostgreSQL advisory lock held while migrating: an
+// arbitrary number that other users of the database don't lock.
+const migrationLock = 0x6c6561726e676f // "learngo"
+
+func migrate(ctx context.Context, db *sql.DB, ms []Migration) (int, error) {
+	for {
+		v, done, err := step(ctx, db, ms)
+		if err != nil || done {
+			return v, err
+		}
+	}
+}
+
+// step applies the next migration, if any, and returns the version db is
+// at afterwards. The transaction first takes migrationLock, which keeps a
+// second migrator waiting until this one commits. Locking the row of
+// schema_version instead wouldn't do: on a fresh database there is no row
+// to lock, nor even a table, and both migrators would start from 0.
+func step(ctx context.Context, db *sql.DB, ms []Migration) (version int, done bool, err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, false, fmt.Errorf("catalog: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, int64(migrationLock)); err != nil {
+		return 0, false, fmt.Errorf("catalog: lock for migration: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_version (version INTEGER NOT NULL)`); err != nil {
+		return 0, false, fmt.Errorf("catalog: %w", err)
+	}
+	var v int
+	err = tx.QueryRowContext(ctx, `SELECT version FROM schema_version`).Scan(&v)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_version (version) VALUES (0)`); err != nil {
+			return 0, false, fmt.Errorf("catalog: %w", err)
+		}
+	case err != nil:
+		return 0, false, fmt.Errorf("catalog: schema version: %w", err)
+	}
+
+	if v > len(ms) {
+		return v, true, fmt.Errorf("catalog: database schema is version %d, newer than this program's %d", v, len(ms))
+	}
+	if v == len(ms) {
+		return v, true, tx.Commit()
+	}
+
+	m := ms[v]
+	for _, stmt := range m.Statements {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return v, false, fmt.Errorf("catalog: migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE schema_version SET version = $1`, m.Version); err != nil {
+		return v, false, fmt.Errorf("catalog: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return v, false, fmt.Errorf("catalog: migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+	return m.Version, false, nil
+}
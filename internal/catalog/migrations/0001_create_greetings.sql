@@ -0,0 +1,5 @@
+-- One greeting prefix per language, such as 'Hola, ' for 'Spanish'.
+CREATE TABLE greetings (
+    language TEXT PRIMARY KEY,
+    prefix   TEXT NOT NULL
+);
@@ -0,0 +1,5 @@
+-- The languages 01-hello-world started with.
+INSERT INTO greetings (language, prefix) VALUES
+    ('English', 'Hello, '),
+    ('Spanish', 'Hola, '),
+    ('French', 'Bonjour, ');
@@ -0,0 +1,291 @@
+// Package chapters finds the numbered chapter directories (01-hello-world,
+// 02-integers, ...) of a module and runs their tests.
+package chapters
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Chapter is one numbered directory, such as 02-integers.
+type Chapter struct {
+	Number int
+	Name   string // directory name, e.g. "02-integers"
+	Dir    string // absolute path
+}
+
+var chapterName = regexp.MustCompile(`^(\d+)-[a-z0-9][a-z0-9-]*$`)
+
+// Discover returns the chapters directly under root in chapter order.
+func Discover(root string) ([]Chapter, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var chapters []Chapter
+	for _, e := range entries {
+		m := chapterName.FindStringSubmatch(e.Name())
+		if !e.IsDir() || m == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[1])
+		chapters = append(chapters, Chapter{Number: n, Name: e.Name(), Dir: filepath.Join(root, e.Name())})
+	}
+	sort.Slice(chapters, func(i, j int) bool { return chapters[i].Number < chapters[j].Number })
+	return chapters, nil
+}
+
+// FindRoot walks up from dir to the directory holding go.mod.
+func FindRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("no go.mod found")
+		}
+		dir = parent
+	}
+}
+
+var moduleLine = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+
+// ModulePath returns the module path declared in root's go.mod.
+func ModulePath(root string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+	m := moduleLine.FindSubmatch(data)
+	if m == nil {
+		return "", errors.New("go.mod has no module line")
+	}
+	return string(m[1]), nil
+}
+
+// Result is the outcome of running one chapter's tests.
+type Result struct {
+	Chapter Chapter
+	Passed  bool
+	// Failure is the first failing assertion, such as
+	// `hello_test.go:12: got "Hola, World" want "Hola, Mundo"`, or the
+	// first build error if the chapter doesn't compile.
+	Failure string
+	// Output is everything go test printed for the failing tests.
+	Output string
+	// Failed names the failing tests, subtests before their parents,
+	// e.g. "TestHello/in_Spanish" then "TestHello".
+	Failed []string
+	// Sum is the chapter's Fingerprint when its tests ran.
+	Sum string
+}
+
+// Fingerprint returns a hash of the names and contents of the files in
+// ch and of the packages in the same module that ch or its tests import,
+// which changes whenever the chapter or code it builds on is edited.
+func Fingerprint(ch Chapter) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(ch.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(ch.Dir, path)
+		return hashFile(h, filepath.ToSlash(rel), path)
+	})
+	if err != nil {
+		return "", fmt.Errorf("fingerprint %s: %w", ch.Name, err)
+	}
+
+	deps, err := moduleDeps(ch)
+	if err != nil {
+		return "", fmt.Errorf("fingerprint %s: %w", ch.Name, err)
+	}
+	for _, p := range deps {
+		for _, name := range p.files() {
+			if err := hashFile(h, p.ImportPath+"/"+name, filepath.Join(p.Dir, name)); err != nil {
+				return "", fmt.Errorf("fingerprint %s: %w", ch.Name, err)
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashFile(h io.Writer, name, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(h, "%s %d\n", name, len(data))
+	h.Write(data)
+	return nil
+}
+
+// pkg is the part of `go list -json` output Fingerprint needs.
+type pkg struct {
+	ImportPath string
+	Dir        string
+	Module     *struct{ Main bool }
+	GoFiles    []string
+	CgoFiles   []string
+	EmbedFiles []string
+}
+
+func (p pkg) files() []string {
+	return slices.Concat(p.GoFiles, p.CgoFiles, p.EmbedFiles)
+}
+
+// moduleDeps lists the packages outside ch.Dir but inside its module that
+// ch and its tests import, directly or not. Other modules and the
+// standard library change only when go.mod does, so they're left out.
+func moduleDeps(ch Chapter) ([]pkg, error) {
+	cmd := exec.Command("go", "list", "-e", "-deps", "-test", "-json=ImportPath,Dir,Module,GoFiles,CgoFiles,EmbedFiles", ".")
+	cmd.Dir = ch.Dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list: %w: %s", err, firstLine(stderr.String()))
+	}
+
+	var deps []pkg
+	seen := map[string]bool{ch.Dir: true}
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for dec.More() {
+		var p pkg
+		if err := dec.Decode(&p); err != nil {
+			return nil, fmt.Errorf("go list: %w", err)
+		}
+		if p.Module == nil || !p.Module.Main || seen[p.Dir] {
+			continue
+		}
+		seen[p.Dir] = true
+		deps = append(deps, p)
+	}
+	sort.Slice(deps, func(i, j int) bool { return deps[i].ImportPath < deps[j].ImportPath })
+	return deps, nil
+}
+
+// Run runs `go test` for ch.
+func Run(ctx context.Context, ch Chapter) (Result, error) {
+	sum, err := Fingerprint(ch)
+	if err != nil {
+		return Result{}, err
+	}
+	cmd := exec.CommandContext(ctx, "go", "test", "-json", ".")
+	cmd.Dir = ch.Dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return Result{}, fmt.Errorf("go test %s: %w", ch.Name, err)
+	}
+
+	r := parse(out)
+	r.Chapter = ch
+	r.Sum = sum
+	r.Passed = err == nil
+	if !r.Passed && r.Failure == "" {
+		r.Failure = firstLine(stderr.String())
+		r.Output += stderr.String()
+	}
+	return r, ctx.Err()
+}
+
+// event is one line of `go test -json` output.
+type event struct {
+	Action string
+	Test   string
+	Output string
+}
+
+var assertion = regexp.MustCompile(`^\s+(\S+\.go:\d+: .*)`)
+
+func parse(out []byte) Result {
+	var (
+		r       Result
+		outputs = map[string][]string{}
+		failed  []string
+		build   []string
+	)
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		var e event
+		if json.Unmarshal(sc.Bytes(), &e) != nil {
+			continue
+		}
+		switch {
+		case e.Action == "build-output":
+			build = append(build, e.Output)
+		case e.Action == "output" && e.Test != "":
+			outputs[e.Test] = append(outputs[e.Test], e.Output)
+		case e.Action == "fail" && e.Test != "":
+			failed = append(failed, e.Test)
+		}
+	}
+
+	// Subtests finish before their parents, so the first failure is the
+	// most specific one.
+	for _, test := range failed {
+		for _, line := range outputs[test] {
+			if m := assertion.FindStringSubmatch(line); m != nil && r.Failure == "" {
+				r.Failure = strings.TrimSpace(m[1])
+			}
+		}
+		r.Output += strings.Join(outputs[test], "")
+	}
+	// Examples have no file:line, only their got/want output.
+	for _, test := range failed {
+		if r.Failure != "" {
+			break
+		}
+		var msg []string
+		for _, line := range outputs[test] {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "=== ") && !strings.HasPrefix(line, "--- ") {
+				msg = append(msg, line)
+			}
+		}
+		if len(msg) > 0 {
+			r.Failure = test + ": " + strings.Join(msg, " ")
+		}
+	}
+	r.Failed = failed
+	if r.Failure == "" && len(build) > 0 {
+		r.Failure = firstLine(strings.Join(build, ""))
+		r.Output = strings.Join(build, "")
+	}
+	return r
+}
+
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			return line
+		}
+	}
+	return ""
+}
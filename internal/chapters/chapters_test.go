@@ -0,0 +1,183 @@
+package chapters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"10-generics", "02-integers", "01-hello-world", "cmd", "notes-01"} {
+		os.Mkdir(filepath.Join(root, dir), 0o755)
+	}
+
+	chapters, err := Discover(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, ch := range chapters {
+		got = append(got, ch.Name)
+	}
+	assertNames(t, got, []string{"01-hello-world", "02-integers", "10-generics"})
+}
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+	root := writeModule(t, map[string]string{
+		"01-pass/pass.go":            "package pass\n\nfunc One() int { return 1 }\n",
+		"01-pass/pass_test.go":       "package pass\n\nimport \"testing\"\n\nfunc TestOne(t *testing.T) {\n\tif One() != 1 {\n\t\tt.Error(\"not one\")\n\t}\n}\n",
+		"02-fail/fail.go":            "package fail\n\nfunc Two() int { return 3 }\n",
+		"02-fail/fail_test.go":       "package fail\n\nimport \"testing\"\n\nfunc TestTwo(t *testing.T) {\n\tt.Run(\"two\", func(t *testing.T) {\n\t\tt.Errorf(\"got %d want %d\", Two(), 2)\n\t})\n}\n",
+		"03-broken/broken.go":        "package broken\n\nfunc Three() int { return \"3\" }\n",
+		"04-example/example.go":      "package example\n\nfunc Four() int { return 5 }\n",
+		"04-example/example_test.go": "package example\n\nimport \"fmt\"\n\nfunc ExampleFour() {\n\tfmt.Println(Four())\n\t// Output: 4\n}\n",
+	})
+	chapters, err := Discover(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var results []Result
+	for _, ch := range chapters {
+		r, err := Run(context.Background(), ch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, r)
+	}
+
+	if !results[0].Passed {
+		t.Errorf("01-pass failed: %s", results[0].Output)
+	}
+	if results[1].Passed || results[1].Failure != "fail_test.go:7: got 3 want 2" {
+		t.Errorf("02-fail: got failure %q", results[1].Failure)
+	}
+	assertNames(t, results[1].Failed, []string{"TestTwo/two", "TestTwo"})
+	if results[2].Passed || results[2].Failure == "" {
+		t.Errorf("03-broken: got failure %q", results[2].Failure)
+	}
+	if results[3].Passed || results[3].Failure != "ExampleFour: got: 5 want: 4" {
+		t.Errorf("04-example: got failure %q", results[3].Failure)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"01-hello-world/hello.go": "package main\n",
+		"02-integers/adder.go":    "package integers\n",
+	})
+	chapters, err := Discover(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := Fingerprint(chapters[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadProgress(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Record(Result{Chapter: chapters[0], Passed: true, Sum: sum})
+	p.Record(Result{Chapter: chapters[1], Failure: "adder_test.go:12: expected 4"})
+	if err := p.Save(root); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err = LoadProgress(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, ok := p.Next(chapters)
+	if !ok || next.Name != "02-integers" {
+		t.Errorf("got next %q want %q", next.Name, "02-integers")
+	}
+	if got := p.Chapters["02-integers"].Failure; got != "adder_test.go:12: expected 4" {
+		t.Errorf("got failure %q", got)
+	}
+
+	// Editing a chapter that passed puts it back in line: it may fail now.
+	hello := filepath.Join(chapters[0].Dir, "hello.go")
+	if err := os.WriteFile(hello, []byte("package main\n\nfunc broken(\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if next, ok := p.Next(chapters); !ok || next.Name != "01-hello-world" {
+		t.Errorf("after an edit, got next %q want %q", next.Name, "01-hello-world")
+	}
+}
+
+func TestFingerprintImports(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"01-hello-world/hello.go":      "package main\n\nimport \"example.com/chapters/internal/words\"\n\nfunc main() { println(words.Hello) }\n",
+		"01-hello-world/hello_test.go": "package main\n\nimport (\n\t\"testing\"\n\n\t\"example.com/chapters/internal/check\"\n)\n\nfunc TestHello(t *testing.T) { check.True(t) }\n",
+		"internal/words/words.go":      "package words\n\nconst Hello = \"Hello\"\n",
+		"internal/check/check.go":      "package check\n\nimport \"testing\"\n\nfunc True(t *testing.T) {}\n",
+		"internal/other/other.go":      "package other\n",
+	})
+	chapters, err := Discover(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := Fingerprint(chapters[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		file    string
+		changes bool
+	}{
+		{"internal/other/other.go", false},
+		{"internal/words/words.go", true},
+		{"internal/check/check.go", true},
+	} {
+		path := filepath.Join(root, tt.file)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, append(data, "// edited\n"...), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		after, err := Fingerprint(chapters[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changed := after != before; changed != tt.changes {
+			t.Errorf("editing %s: got changed %v want %v", tt.file, changed, tt.changes)
+		}
+		before = after
+	}
+}
+
+func writeModule(t testing.TB, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	files["go.mod"] = "module example.com/chapters\n\ngo 1.24\n"
+	for name, src := range files {
+		path := filepath.Join(root, name)
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func assertNames(t testing.TB, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %q want %q", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package chapters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ProgressFile is where progress is kept, relative to the module root.
+const ProgressFile = ".learn/progress.json"
+
+// Status is the last known state of a chapter.
+type Status struct {
+	Passed  bool      `json:"passed"`
+	Failure string    `json:"failure,omitempty"`
+	RunAt   time.Time `json:"run_at"`
+	Sum     string    `json:"sum,omitempty"` // the chapter's Fingerprint when it ran
+}
+
+// Progress maps chapter names to their last status.
+type Progress struct {
+	Chapters map[string]Status `json:"chapters"`
+}
+
+// LoadProgress reads the progress saved under root. No saved progress
+// is not an error.
+func LoadProgress(root string) (*Progress, error) {
+	p := &Progress{Chapters: map[string]Status{}}
+	data, err := os.ReadFile(filepath.Join(root, ProgressFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("load progress: %w", err)
+	}
+	if p.Chapters == nil {
+		p.Chapters = map[string]Status{}
+	}
+	return p, nil
+}
+
+// Save writes p under root.
+func (p *Progress) Save(root string) error {
+	path := filepath.Join(root, ProgressFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Record stores the outcome of a run.
+func (p *Progress) Record(r Result) {
+	p.Chapters[r.Chapter.Name] = Status{Passed: r.Passed, Failure: r.Failure, RunAt: time.Now(), Sum: r.Sum}
+}
+
+// Next returns the first chapter that isn't known to pass, and false once
+// every chapter is. A pass only counts while the chapter's files are the
+// ones that passed: a chapter edited since may fail now, so it is
+// offered again.
+func (p *Progress) Next(chapters []Chapter) (Chapter, bool) {
+	for _, ch := range chapters {
+		if !p.passing(ch) {
+			return ch, true
+		}
+	}
+	return Chapter{}, false
+}
+
+func (p *Progress) passing(ch Chapter) bool {
+	st := p.Chapters[ch.Name]
+	if !st.Passed || st.Sum == "" {
+		return false
+	}
+	sum, err := Fingerprint(ch)
+	return err == nil && sum == st.Sum
+}
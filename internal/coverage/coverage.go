@@ -0,0 +1,292 @@
+// Package coverage measures how much of each chapter its tests cover,
+// points at the code no test runs, such as a switch case nobody wrote a
+// test for, and checks each chapter against a minimum. A chapter whose
+// tests fail is below its minimum whatever its coverage.
+package coverage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"learn-go/internal/chapters"
+)
+
+// ConfigFile holds the coverage thresholds, relative to the module root.
+const ConfigFile = "coverage.json"
+
+// DefaultThreshold is the minimum coverage, in percent, of a chapter the
+// config doesn't mention.
+const DefaultThreshold = 80
+
+// Config is the minimum coverage, in percent, each chapter must reach.
+type Config struct {
+	Default  float64            `json:"default"`
+	Chapters map[string]float64 `json:"chapters,omitempty"`
+}
+
+// LoadConfig reads the config under root.
+func LoadConfig(root string) (Config, error) {
+	c := Config{Default: DefaultThreshold}
+	data, err := os.ReadFile(filepath.Join(root, ConfigFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return Config{}, err
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", ConfigFile, err)
+	}
+	return c, nil
+}
+
+// Threshold returns the minimum coverage of the named chapter.
+func (c Config) Threshold(chapter string) float64 {
+	if t, ok := c.Chapters[chapter]; ok {
+		return t
+	}
+	return c.Default
+}
+
+// Report is the coverage of one chapter. Code in func main isn't counted:
+// tests can't call it.
+type Report struct {
+	Chapter    chapters.Chapter
+	Profile    *Profile
+	Statements int
+	Covered    int
+	Threshold  float64
+	// Files are the chapter's source files, line by line.
+	Files []File
+	// Gaps are the blocks no test runs, in file order.
+	Gaps []Gap
+	// TestsFailed is set when go test failed. The coverage is then that
+	// of the tests that ran, or zero if the chapter doesn't build.
+	TestsFailed bool
+	Output      string
+}
+
+// Percent is the share of statements covered.
+func (r *Report) Percent() float64 {
+	if r.Statements == 0 {
+		return 100
+	}
+	return 100 * float64(r.Covered) / float64(r.Statements)
+}
+
+// OK reports whether the tests pass and cover at least the threshold.
+func (r *Report) OK() bool {
+	return !r.TestsFailed && r.Percent() >= r.Threshold
+}
+
+// File is a source file of a chapter.
+type File struct {
+	Name  string // relative to the module root
+	Lines []Line
+}
+
+// Line is a line of source and whether tests run it.
+type Line struct {
+	Number int
+	Text   string
+	State  State
+}
+
+// State tells how a line is covered.
+type State string
+
+const (
+	NoCode    State = ""
+	Covered   State = "covered"
+	Uncovered State = "uncovered"
+	Ignored   State = "ignored" // in func main
+)
+
+// Gap is a block of code no test runs.
+type Gap struct {
+	File      string // relative to the module root
+	Line      int
+	EndLine   int
+	Func      string
+	Statement string // the block's first line
+}
+
+func (g Gap) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s", g.File, g.Line, g.Func, g.Statement)
+}
+
+// Run runs ch's tests with coverage and reports on them against the
+// threshold cfg sets for ch.
+func Run(ctx context.Context, root string, cfg Config, ch chapters.Chapter) (*Report, error) {
+	out, err := os.CreateTemp("", "learn-cover-*.out")
+	if err != nil {
+		return nil, err
+	}
+	out.Close()
+	defer os.Remove(out.Name())
+
+	cmd := exec.CommandContext(ctx, "go", "test", "-covermode=set", "-coverprofile="+out.Name(), ".")
+	cmd.Dir = ch.Dir
+	output, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return nil, fmt.Errorf("go test %s: %w", ch.Name, err)
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	r := &Report{Chapter: ch, Threshold: cfg.Threshold(ch.Name), TestsFailed: err != nil, Output: string(output)}
+	data, _ := os.ReadFile(out.Name())
+	if len(data) == 0 {
+		// The chapter doesn't build, so no tests ran.
+		r.Profile = &Profile{Mode: "set"}
+		return r, nil
+	}
+	if r.Profile, err = ParseProfile(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	if err := r.analyze(root); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// analyze counts r's statements and lays its blocks over the source.
+func (r *Report) analyze(root string) error {
+	module, err := chapters.ModulePath(root)
+	if err != nil {
+		return err
+	}
+
+	byFile := map[string][]Block{}
+	var names []string
+	for _, b := range r.Profile.Blocks {
+		if _, ok := byFile[b.File]; !ok {
+			names = append(names, b.File)
+		}
+		byFile[b.File] = append(byFile[b.File], b)
+	}
+
+	for _, importPath := range names {
+		name := strings.TrimPrefix(importPath, module+"/")
+		path := filepath.Join(root, filepath.FromSlash(name))
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		funcs, err := funcLines(path, src)
+		if err != nil {
+			return err
+		}
+
+		lines := strings.Split(strings.TrimSuffix(string(src), "\n"), "\n")
+		f := File{Name: name, Lines: make([]Line, len(lines))}
+		for i, text := range lines {
+			f.Lines[i] = Line{Number: i + 1, Text: text}
+		}
+		for _, b := range byFile[importPath] {
+			fn := funcs.at(b.StartLine)
+			state := Covered
+			switch {
+			case fn == "main":
+				state = Ignored
+			case b.Count == 0:
+				state = Uncovered
+			}
+			if state != Ignored {
+				r.Statements += b.Statements
+				if state == Covered {
+					r.Covered += b.Statements
+				}
+			}
+
+			// A block usually ends at the start of the line with its
+			// closing brace, which isn't really part of it.
+			end := b.EndLine
+			if b.EndCol <= 1 && end > b.StartLine {
+				end--
+			}
+			end = min(end, len(f.Lines))
+			for n := b.StartLine; n <= end; n++ {
+				// A line is only as covered as its least covered block.
+				if l := &f.Lines[n-1]; l.State == NoCode || state == Uncovered {
+					l.State = state
+				}
+			}
+			if state == Uncovered {
+				r.Gaps = append(r.Gaps, Gap{
+					File:      name,
+					Line:      b.StartLine,
+					EndLine:   end,
+					Func:      fn,
+					Statement: strings.TrimSpace(lines[b.StartLine-1]),
+				})
+			}
+		}
+		r.Files = append(r.Files, f)
+	}
+	return nil
+}
+
+// funcRange is the lines of a function declaration.
+type funcRange struct {
+	name       string
+	start, end int
+}
+
+type funcs []funcRange
+
+func (fs funcs) at(line int) string {
+	for _, f := range fs {
+		if f.start <= line && line <= f.end {
+			return f.name
+		}
+	}
+	return ""
+}
+
+func funcLines(path string, src []byte) (funcs, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, src, 0)
+	if err != nil {
+		return nil, err
+	}
+	var fs funcs
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		name := fn.Name.Name
+		if fn.Recv != nil && len(fn.Recv.List) == 1 {
+			name = recvName(fn.Recv.List[0].Type) + "." + name
+		}
+		fs = append(fs, funcRange{name, fset.Position(fn.Pos()).Line, fset.Position(fn.End()).Line})
+	}
+	return fs, nil
+}
+
+func recvName(x ast.Expr) string {
+	switch x := x.(type) {
+	case *ast.StarExpr:
+		return recvName(x.X)
+	case *ast.IndexExpr:
+		return recvName(x.X)
+	case *ast.IndexListExpr:
+		return recvName(x.X)
+	case *ast.Ident:
+		return x.Name
+	}
+	return "?"
+}
@@ -0,0 +1,130 @@
+package coverage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"learn-go/internal/chapters"
+)
+
+const profileA = `mode: set
+example.com/m/01-a/a.go:5.2,5.12 1 1
+example.com/m/01-a/a.go:3.2,3.10 2 0
+`
+
+const profileB = `mode: set
+example.com/m/01-a/a.go:3.2,3.10 2 1
+example.com/m/02-b/b.go:1.1,2.1 1 0
+`
+
+func TestMerge(t *testing.T) {
+	a, err := ParseProfile(strings.NewReader(profileA))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ParseProfile(strings.NewReader(profileB))
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, err := Merge(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got strings.Builder
+	merged.Write(&got)
+	want := `mode: set
+example.com/m/01-a/a.go:3.2,3.10 2 1
+example.com/m/01-a/a.go:5.2,5.12 1 1
+example.com/m/02-b/b.go:1.1,2.1 1 0
+`
+	if got.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", got.String(), want)
+	}
+
+	if _, err := Merge(a, &Profile{Mode: "count"}); err == nil {
+		t.Error("merged set and count profiles")
+	}
+	if _, err := ParseProfile(strings.NewReader("mode: set\na.go:1.1 1 1\n")); err == nil {
+		t.Error("parsed a malformed block")
+	}
+}
+
+const greet = `package main
+
+func Greet(language string) string {
+	switch language {
+	case "Spanish":
+		return "Hola"
+	case "French":
+		return "Bonjour"
+	}
+	return "Hello"
+}
+
+func main() {
+	println(Greet("English"))
+}
+`
+
+const greetTest = `package main
+
+import "testing"
+
+func TestGreet(t *testing.T) {
+	if Greet("Spanish") != "Hola" || Greet("") != "Hello" {
+		t.Error("wrong greeting")
+	}
+}
+`
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":                 "module example.com/m\n\ngo 1.24\n",
+		ConfigFile:               `{"default": 50, "chapters": {"01-greet": 90}}`,
+		"01-greet/greet.go":      greet,
+		"01-greet/greet_test.go": greetTest,
+	}
+	for name, src := range files {
+		path := filepath.Join(root, name)
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		os.WriteFile(path, []byte(src), 0o644)
+	}
+	cfg, err := LoadConfig(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, _ := chapters.Discover(root)
+
+	r, err := Run(context.Background(), root, cfg, all[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	// main isn't counted, and neither is the untested French case.
+	if r.Statements != 4 || r.Covered != 3 || r.OK() {
+		t.Errorf("got %d/%d statements covered, ok %v", r.Covered, r.Statements, r.OK())
+	}
+	if len(r.Gaps) != 1 || r.Gaps[0].String() != `01-greet/greet.go:8: Greet: return "Bonjour"` {
+		t.Errorf("got gaps %v", r.Gaps)
+	}
+	if got := r.Files[0].Lines[13].State; got != Ignored {
+		t.Errorf("main's body is %q", got)
+	}
+
+	var text, html strings.Builder
+	WriteText(&text, []*Report{r})
+	if !strings.Contains(text.String(), "75.0%     90%      below minimum") {
+		t.Errorf("got\n%s", text.String())
+	}
+	WriteHTML(&html, []*Report{r})
+	if !strings.Contains(html.String(), `<tr id="01-greet-greet.go-L8" class="uncovered">`) {
+		t.Errorf("French case not highlighted in\n%s", html.String())
+	}
+}
@@ -0,0 +1,125 @@
+package coverage
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Block is one line of a cover profile: a run of statements and how often
+// it ran.
+type Block struct {
+	File                string // import path of the file, e.g. "learn-go/02-integers/adder.go"
+	StartLine, StartCol int
+	EndLine, EndCol     int
+	Statements          int
+	Count               int
+}
+
+// Profile is a parsed `go test -coverprofile` file.
+type Profile struct {
+	Mode   string // "set", "count" or "atomic"
+	Blocks []Block
+}
+
+// ParseProfile reads a profile written by `go test -coverprofile`.
+func ParseProfile(r io.Reader) (*Profile, error) {
+	p := &Profile{}
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if n == 1 {
+			mode, ok := strings.CutPrefix(line, "mode: ")
+			if !ok {
+				return nil, fmt.Errorf("profile: missing mode line")
+			}
+			p.Mode = mode
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		b, err := parseBlock(line)
+		if err != nil {
+			return nil, fmt.Errorf("profile line %d: %w", n, err)
+		}
+		p.Blocks = append(p.Blocks, b)
+	}
+	return p, sc.Err()
+}
+
+// parseBlock parses "file.go:19.2,21.1 1 1".
+func parseBlock(line string) (Block, error) {
+	colon := strings.LastIndexByte(line, ':')
+	if colon < 0 {
+		return Block{}, fmt.Errorf("malformed %q", line)
+	}
+	b := Block{File: line[:colon]}
+	var nums [6]int
+	fields := strings.FieldsFunc(line[colon+1:], func(r rune) bool { return r == '.' || r == ',' || r == ' ' })
+	if len(fields) != len(nums) {
+		return Block{}, fmt.Errorf("malformed %q", line)
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return Block{}, fmt.Errorf("malformed %q", line)
+		}
+		nums[i] = n
+	}
+	b.StartLine, b.StartCol, b.EndLine, b.EndCol, b.Statements, b.Count = nums[0], nums[1], nums[2], nums[3], nums[4], nums[5]
+	return b, nil
+}
+
+// Write writes p in the format `go tool cover` reads.
+func (p *Profile) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "mode: %s\n", p.Mode)
+	for _, b := range p.Blocks {
+		fmt.Fprintf(bw, "%s:%d.%d,%d.%d %d %d\n", b.File, b.StartLine, b.StartCol, b.EndLine, b.EndCol, b.Statements, b.Count)
+	}
+	return bw.Flush()
+}
+
+// Merge combines profiles into one, sorted by file and position. A block
+// that appears in several profiles is covered if any of them covered it;
+// in count mode the counts add up.
+func Merge(profiles ...*Profile) (*Profile, error) {
+	merged := &Profile{}
+	index := map[Block]int{}
+	for _, p := range profiles {
+		if merged.Mode == "" {
+			merged.Mode = p.Mode
+		}
+		if p.Mode != merged.Mode {
+			return nil, fmt.Errorf("can't merge %s and %s profiles", merged.Mode, p.Mode)
+		}
+		for _, b := range p.Blocks {
+			key := b
+			key.Count = 0
+			i, ok := index[key]
+			if !ok {
+				index[key] = len(merged.Blocks)
+				merged.Blocks = append(merged.Blocks, b)
+				continue
+			}
+			if merged.Mode == "set" {
+				merged.Blocks[i].Count = max(merged.Blocks[i].Count, b.Count)
+			} else {
+				merged.Blocks[i].Count += b.Count
+			}
+		}
+	}
+	slices.SortFunc(merged.Blocks, func(a, b Block) int {
+		return cmp.Or(
+			strings.Compare(a.File, b.File),
+			cmp.Compare(a.StartLine, b.StartLine),
+			cmp.Compare(a.StartCol, b.StartCol),
+		)
+	})
+	return merged, nil
+}
@@ -0,0 +1,89 @@
+package coverage
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// WriteText writes a table of the reports' coverage followed by the code
+// no test runs.
+func WriteText(w io.Writer, reports []*Report) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHAPTER\tCOVERAGE\tMINIMUM\t")
+	for _, r := range reports {
+		fmt.Fprintf(tw, "%s\t%.1f%%\t%g%%\t%s\n", r.Chapter.Name, r.Percent(), r.Threshold, verdict(r))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, r := range reports {
+		if len(r.Gaps) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\nNot run by any test in %s:\n", r.Chapter.Name)
+		for _, g := range r.Gaps {
+			fmt.Fprintf(w, "  %s\n", g)
+		}
+	}
+	return nil
+}
+
+func verdict(r *Report) string {
+	switch {
+	case r.TestsFailed:
+		return "tests fail"
+	case !r.OK():
+		return "below minimum"
+	}
+	return "ok"
+}
+
+// WriteHTML writes a page with the reports' coverage and their source,
+// the code no test runs in red.
+func WriteHTML(w io.Writer, reports []*Report) error {
+	return page.Execute(w, reports)
+}
+
+var page = template.Must(template.New("coverage").Funcs(template.FuncMap{
+	"verdict": verdict,
+	"anchor": func(file string, line int) string {
+		return fmt.Sprintf("%s-L%d", strings.ReplaceAll(file, "/", "-"), line)
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Chapter coverage</title>
+<style>
+body { font-family: sans-serif; max-width: 60rem; margin: 2rem auto; }
+table.summary td, table.summary th { padding: 0.2rem 1rem; text-align: left; }
+tr.fail { color: #b00; }
+table.source { border-collapse: collapse; font-family: monospace; white-space: pre; tab-size: 4; }
+table.source td.n { color: #999; text-align: right; padding-right: 1rem; user-select: none; }
+.covered { background: #e6ffed; }
+.uncovered { background: #ffdce0; }
+.ignored { color: #999; }
+</style>
+</head>
+<body>
+<h1>Chapter coverage</h1>
+<table class="summary">
+<tr><th>Chapter</th><th>Coverage</th><th>Minimum</th><th></th></tr>
+{{range .}}<tr{{if not .OK}} class="fail"{{end}}><td><a href="#{{.Chapter.Name}}">{{.Chapter.Name}}</a></td><td>{{printf "%.1f%%" .Percent}}</td><td>{{.Threshold}}%</td><td>{{verdict .}}</td></tr>
+{{end}}</table>
+<p>Green lines run in tests, red ones don't. Grey code is in <code>func main</code>, which tests can't call.</p>
+{{range .}}<h2 id="{{.Chapter.Name}}">{{.Chapter.Name}}</h2>
+{{if .Gaps}}<ul>
+{{range .Gaps}}<li><a href="#{{anchor .File .Line}}">{{.File}}:{{.Line}}</a> in {{.Func}}: <code>{{.Statement}}</code></li>
+{{end}}</ul>
+{{end}}{{range .Files}}<h3>{{.Name}}</h3>
+<table class="source">
+{{$file := .Name}}{{range .Lines}}<tr id="{{anchor $file .Number}}" class="{{.State}}"><td class="n">{{.Number}}</td><td>{{.Text}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}</body>
+</html>
+`))
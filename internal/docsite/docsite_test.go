@@ -0,0 +1,136 @@
+package docsite
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const readme = "# Learn Go\n\n" +
+	"## Testing style\n\n" +
+	"Examples like `Add` show up in the docs.\n\n" +
+	"## Chapters\n\n" +
+	"### 01-sums\n\n" +
+	"- Code: [`01-sums`](01-sums)\n"
+
+func newModule(t testing.TB) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":    "module example.com/learn\n\ngo 1.24\n",
+		"README.md": readme,
+		"01-sums/sums.go": "// Package sums adds numbers.\npackage sums\n\n" +
+			"// Add returns a plus b.\nfunc Add(a, b int) int { return a + b }\n",
+		"01-sums/sums_test.go": "package sums\n\nimport \"fmt\"\n\n" +
+			"func ExampleAdd() {\n\tfmt.Println(Add(5, 3))\n\t// Output: 8\n}\n",
+		"02-more/more.go": "package more\n",
+	}
+	for name, src := range files {
+		path := filepath.Join(root, name)
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestLoad(t *testing.T) {
+	site, err := Load(newModule(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := site.Chapter("01-sums")
+	if c == nil || len(c.Examples) != 1 {
+		t.Fatalf("got chapter %+v", c)
+	}
+	ex := c.Examples[0]
+	if ex.Func != "ExampleAdd" || ex.Output != "8\n" || ex.Source() != "fmt.Println(Add(5, 3))" {
+		t.Errorf("got example %s output %q source %q", ex.Func, ex.Output, ex.Source())
+	}
+
+	_, next := site.Neighbours(c)
+	if next == nil || next.Name != "02-more" {
+		t.Errorf("got next chapter %v want 02-more", next)
+	}
+
+	var related []string
+	for _, sec := range site.Related(c) {
+		related = append(related, sec.Title)
+	}
+	if strings.Join(related, ",") != "Testing style,01-sums" {
+		t.Errorf("got related sections %q", related)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	srv := httptest.NewServer(Handler(newModule(t)))
+	defer srv.Close()
+
+	page := get(t, srv.URL+"/chapter/01-sums")
+	for _, want := range []string{"Add returns a plus b.", `<pre class="output"><code>8`, `data-url="/chapter/01-sums/run/ExampleAdd"`, `href="/chapter/02-more"`, `href="/readme#testing-style"`} {
+		if !strings.Contains(page, want) {
+			t.Errorf("chapter page is missing %s", want)
+		}
+	}
+
+	if readme := get(t, srv.URL+"/readme"); !strings.Contains(readme, `<a href="/chapter/01-sums"><code>01-sums</code></a>`) {
+		t.Errorf("README doesn't link to the chapter:\n%s", readme)
+	}
+}
+
+func TestRunExample(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+	site, err := Load(newModule(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := site.Chapter("01-sums")
+
+	got, err := RunExample(context.Background(), c, c.Examples[0])
+	if err != nil {
+		t.Fatalf("%v\n%s", err, got)
+	}
+	if got != "8\n" {
+		t.Errorf("got %q want %q", got, "8\n")
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	src := "Use **`go test`** <!-- snippet-check skip -->\nto run [tests](01-sums).\n\n" +
+		"- one\n- two\n\n" +
+		"| a | b |\n| --- | --- |\n| 1 | 2 |\n\n" +
+		"```go\nx := a < b\n```\n"
+	md := markdown{link: func(url string) string { return "/chapter/" + url }}
+
+	got := md.render(src)
+	want := "<p>Use <strong><code>go test</code></strong> to run <a href=\"/chapter/01-sums\">tests</a>.</p>\n" +
+		"<ul>\n<li>one</li>\n<li>two</li>\n</ul>\n" +
+		"<table>\n<tr><th>a</th><th>b</th></tr>\n<tr><td>1</td><td>2</td></tr>\n</table>\n" +
+		"<pre class=\"go\"><code>x := a &lt; b</code></pre>\n"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func get(t testing.TB, url string) string {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: %s\n%s", url, resp.Status, body)
+	}
+	return string(body)
+}
@@ -0,0 +1,143 @@
+package docsite
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// markdown renders the subset of Markdown the README uses: headings,
+// paragraphs, lists, block quotes, tables and fenced code, with inline
+// code, bold and links. HTML comments are dropped.
+type markdown struct {
+	// link rewrites link targets, so links to chapter directories can
+	// point at chapter pages instead.
+	link func(url string) string
+}
+
+var htmlComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+func (m markdown) render(src string) string {
+	src = htmlComment.ReplaceAllString(src, "")
+
+	var (
+		out   strings.Builder
+		para  []string
+		list  bool
+		table [][]string
+	)
+	flush := func() {
+		if len(para) > 0 {
+			out.WriteString("<p>" + m.inline(strings.Join(para, " ")) + "</p>\n")
+			para = nil
+		}
+		if list {
+			out.WriteString("</ul>\n")
+			list = false
+		}
+		if table != nil {
+			out.WriteString("<table>\n")
+			for i, row := range table {
+				cell := "td"
+				if i == 0 {
+					cell = "th"
+				}
+				out.WriteString("<tr>")
+				for _, c := range row {
+					out.WriteString("<" + cell + ">" + m.inline(c) + "</" + cell + ">")
+				}
+				out.WriteString("</tr>\n")
+			}
+			out.WriteString("</table>\n")
+			table = nil
+		}
+	}
+
+	lines := strings.Split(src, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			lang := strings.TrimPrefix(trimmed, "```")
+			var code []string
+			for i++; i < len(lines) && strings.TrimSpace(lines[i]) != "```"; i++ {
+				code = append(code, lines[i])
+			}
+			out.WriteString(`<pre class="` + html.EscapeString(lang) + `"><code>` + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "#"):
+			flush()
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			title := strings.TrimSpace(trimmed[level:])
+			tag := "h" + string(rune('0'+min(level, 6)))
+			out.WriteString("<" + tag + ` id="` + slug(title) + `">` + m.inline(title) + "</" + tag + ">\n")
+		case strings.HasPrefix(trimmed, "|"):
+			if len(para) > 0 || list {
+				flush()
+			}
+			cells := strings.Split(strings.Trim(trimmed, "|"), "|")
+			if strings.Trim(strings.Join(cells, ""), " -:") == "" {
+				continue // the header separator row
+			}
+			for j := range cells {
+				cells[j] = strings.TrimSpace(cells[j])
+			}
+			table = append(table, cells)
+		case strings.HasPrefix(trimmed, "- "):
+			if len(para) > 0 || table != nil {
+				flush()
+			}
+			if !list {
+				out.WriteString("<ul>\n")
+				list = true
+			}
+			out.WriteString("<li>" + m.inline(strings.TrimPrefix(trimmed, "- ")) + "</li>\n")
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			out.WriteString("<blockquote>" + m.inline(strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))) + "</blockquote>\n")
+		default:
+			if list || table != nil {
+				flush()
+			}
+			para = append(para, trimmed)
+		}
+	}
+	flush()
+	return out.String()
+}
+
+var (
+	inlineCode = regexp.MustCompile("`([^`]+)`")
+	bold       = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	link       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+)
+
+// inline renders inline markup in an escaped line. Code spans are set
+// aside first so nothing inside them is touched.
+func (m markdown) inline(text string) string {
+	var spans []string
+	text = inlineCode.ReplaceAllStringFunc(text, func(s string) string {
+		spans = append(spans, "<code>"+html.EscapeString(s[1:len(s)-1])+"</code>")
+		return placeholder(len(spans) - 1)
+	})
+	text = html.EscapeString(text)
+	text = bold.ReplaceAllString(text, "<strong>$1</strong>")
+	text = link.ReplaceAllStringFunc(text, func(s string) string {
+		parts := link.FindStringSubmatch(s)
+		target := html.UnescapeString(parts[2])
+		if m.link != nil {
+			target = m.link(target)
+		}
+		return `<a href="` + html.EscapeString(target) + `">` + parts[1] + "</a>"
+	})
+	for i, span := range spans {
+		text = strings.Replace(text, placeholder(i), span, 1)
+	}
+	return text
+}
+
+func placeholder(i int) string { return "\x00" + strconv.Itoa(i) + "\x00" }
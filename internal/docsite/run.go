@@ -0,0 +1,89 @@
+package docsite
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// RunTimeout bounds how long running an example may take, build included.
+const RunTimeout = 30 * time.Second
+
+const (
+	beginOutput = "--- learn docs: begin output ---\n"
+	endOutput   = "\n--- learn docs: end output ---\n"
+)
+
+// RunExample runs the example ex of chapter c and returns what it
+// printed. It calls the example from a test that go test only sees
+// through an -overlay, so nothing is written into the chapter.
+func RunExample(ctx context.Context, c *Chapter, ex *Example) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, RunTimeout)
+	defer cancel()
+
+	tmp, err := os.MkdirTemp("", "learn-docs-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+
+	const testName = "TestLearnDocsRunExample"
+	src := fmt.Sprintf(`package %s
+
+import (
+	"os"
+	"testing"
+)
+
+func %s(t *testing.T) {
+	os.Stdout.WriteString(%q)
+	%s()
+	os.Stdout.WriteString(%q)
+}
+`, ex.Package, testName, beginOutput, ex.Func, endOutput)
+
+	runner := filepath.Join(tmp, "run_test.go")
+	if err := os.WriteFile(runner, []byte(src), 0o644); err != nil {
+		return "", err
+	}
+	overlay, err := json.Marshal(map[string]map[string]string{
+		"Replace": {filepath.Join(c.Dir, "zz_learn_docs_run_test.go"): runner},
+	})
+	if err != nil {
+		return "", err
+	}
+	overlayFile := filepath.Join(tmp, "overlay.json")
+	if err := os.WriteFile(overlayFile, overlay, 0o644); err != nil {
+		return "", err
+	}
+
+	// No package argument: in local directory mode go test passes the
+	// test binary's output straight through.
+	cmd := exec.CommandContext(ctx, "go", "test", "-overlay", overlayFile, "-count=1", "-run", "^"+testName+"$")
+	cmd.Dir = c.Dir
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err = cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return out.String(), fmt.Errorf("%s timed out after %v", ex.Func, RunTimeout)
+	}
+
+	printed := out.String()
+	start := strings.Index(printed, beginOutput)
+	end := strings.Index(printed, endOutput)
+	if start < 0 || end < start {
+		if err == nil {
+			err = errors.New("example output not found")
+		}
+		return printed, err
+	}
+	return printed[start+len(beginOutput) : end], nil
+}
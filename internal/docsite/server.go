@@ -0,0 +1,217 @@
+package docsite
+
+import (
+	"go/doc"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// Handler serves the site for the module at root.
+func Handler(root string) http.Handler {
+	s := &server{root: root}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.index)
+	mux.HandleFunc("GET /readme", s.readme)
+	mux.HandleFunc("GET /chapter/{name}", s.chapter)
+	mux.HandleFunc("POST /chapter/{name}/run/{example}", s.run)
+	return mux
+}
+
+type server struct {
+	root string
+}
+
+func (s *server) load(w http.ResponseWriter) *Site {
+	site, err := Load(s.root)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil
+	}
+	return site
+}
+
+func (s *server) index(w http.ResponseWriter, r *http.Request) {
+	if site := s.load(w); site != nil {
+		render(w, "index", site)
+	}
+}
+
+func (s *server) readme(w http.ResponseWriter, r *http.Request) {
+	if site := s.load(w); site != nil {
+		render(w, "readme", site)
+	}
+}
+
+func (s *server) chapter(w http.ResponseWriter, r *http.Request) {
+	site := s.load(w)
+	if site == nil {
+		return
+	}
+	c := site.Chapter(r.PathValue("name"))
+	if c == nil {
+		http.NotFound(w, r)
+		return
+	}
+	prev, next := site.Neighbours(c)
+	render(w, "chapter", chapterPage{Site: site, Chapter: c, Prev: prev, Next: next, Related: site.Related(c)})
+}
+
+func (s *server) run(w http.ResponseWriter, r *http.Request) {
+	site := s.load(w)
+	if site == nil {
+		return
+	}
+	c := site.Chapter(r.PathValue("name"))
+	if c == nil {
+		http.NotFound(w, r)
+		return
+	}
+	// Only examples the chapter declares can be run.
+	for _, ex := range c.Examples {
+		if ex.Func != r.PathValue("example") {
+			continue
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		out, err := RunExample(r.Context(), c, ex)
+		if err != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			out += "\n" + err.Error()
+		}
+		w.Write([]byte(out))
+		return
+	}
+	http.NotFound(w, r)
+}
+
+type chapterPage struct {
+	Site       *Site
+	Chapter    *Chapter
+	Prev, Next *Chapter
+	Related    []Section
+}
+
+func render(w http.ResponseWriter, name string, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := pages.ExecuteTemplate(w, name, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// chapterLink points links at chapter directories, like the README's
+// "[`02-integers`](02-integers)", to the chapter's page.
+func (s *Site) chapterLink(url string) string {
+	name := strings.Trim(strings.TrimPrefix(url, "./"), "/")
+	if s.Chapter(name) != nil {
+		return "/chapter/" + name
+	}
+	return url
+}
+
+var pages = template.Must(template.New("").Funcs(template.FuncMap{
+	"markdown": func(s *Site, src string) template.HTML {
+		return template.HTML(markdown{link: s.chapterLink}.render(src))
+	},
+	"docHTML": func(p *doc.Package, text string) template.HTML {
+		return template.HTML(p.HTML(text))
+	},
+}).Parse(`
+{{define "header"}}<!doctype html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.}} · Learn Go</title>
+<style>
+body { font: 16px/1.5 system-ui, sans-serif; max-width: 52rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+pre { background: #f4f4f4; padding: .75rem; overflow-x: auto; }
+code { font-family: ui-monospace, monospace; font-size: 90%; }
+nav { margin-bottom: 1.5rem; }
+nav a { margin-right: 1rem; }
+table { border-collapse: collapse; }
+td, th { border: 1px solid #ddd; padding: .25rem .5rem; text-align: left; }
+.output { border-left: 3px solid #7a7; }
+.result { border-left: 3px solid #77a; white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<nav><a href="/">Chapters</a><a href="/readme">README</a></nav>
+{{end}}
+
+{{define "footer"}}</body>
+</html>
+{{end}}
+
+{{define "index"}}{{template "header" "Chapters"}}
+<h1>Learn Go</h1>
+<h2>Chapters</h2>
+<ol>
+{{range .Chapters}}<li><a href="/chapter/{{.Name}}">{{.Name}}</a> {{.Package.Synopsis .Package.Doc}}</li>
+{{end}}</ol>
+<h2>README</h2>
+<ul>
+{{range .Sections}}{{if eq .Level 2}}<li><a href="/readme#{{.Anchor}}">{{.Title}}</a></li>
+{{end}}{{end}}</ul>
+{{template "footer"}}{{end}}
+
+{{define "readme"}}{{template "header" "README"}}
+<h1>Learn Go</h1>
+{{$site := .}}{{range .Sections}}
+{{if eq .Level 2}}<h2 id="{{.Anchor}}">{{.Title}}</h2>{{else}}<h3 id="{{.Anchor}}">{{.Title}}</h3>{{end}}
+{{if $site.Chapter .Title}}<p>Documentation: <a href="/chapter/{{.Title}}">{{.Title}}</a></p>{{end}}
+{{markdown $site .Body}}
+{{end}}
+{{template "footer"}}{{end}}
+
+{{define "chapter"}}{{template "header" .Chapter.Name}}
+{{$c := .Chapter}}{{$pkg := .Chapter.Package}}
+<h1>{{$c.Name}} <small>package {{$pkg.Name}}</small></h1>
+<nav>
+{{with .Prev}}<a href="/chapter/{{.Name}}">← {{.Name}}</a>{{end}}
+{{with .Next}}<a href="/chapter/{{.Name}}">{{.Name}} →</a>{{end}}
+</nav>
+{{docHTML $pkg $pkg.Doc}}
+{{with .Related}}<p>In the README:
+{{range .}}<a href="/readme#{{.Anchor}}">{{.Title}}</a> {{end}}</p>{{end}}
+
+{{with $pkg.Consts}}<h2>Constants</h2>{{range .}}<pre><code>{{$c.Decl .Decl}}</code></pre>{{docHTML $pkg .Doc}}{{end}}{{end}}
+{{with $pkg.Vars}}<h2>Variables</h2>{{range .}}<pre><code>{{$c.Decl .Decl}}</code></pre>{{docHTML $pkg .Doc}}{{end}}{{end}}
+
+{{range $pkg.Funcs}}
+<h2 id="{{.Name}}">func {{.Name}}</h2>
+<pre><code>{{$c.Decl .Decl}}</code></pre>
+{{docHTML $pkg .Doc}}
+{{template "examples" ($c.ExamplesFor .Name)}}
+{{end}}
+
+{{range $pkg.Types}}
+<h2 id="{{.Name}}">type {{.Name}}</h2>
+<pre><code>{{$c.Decl .Decl}}</code></pre>
+{{docHTML $pkg .Doc}}
+{{template "examples" ($c.ExamplesFor .Name)}}
+{{range .Funcs}}<h3 id="{{.Name}}">func {{.Name}}</h3><pre><code>{{$c.Decl .Decl}}</code></pre>{{docHTML $pkg .Doc}}{{end}}
+{{range .Methods}}<h3 id="{{.Recv}}.{{.Name}}">func ({{.Recv}}) {{.Name}}</h3><pre><code>{{$c.Decl .Decl}}</code></pre>{{docHTML $pkg .Doc}}{{end}}
+{{end}}
+
+{{with $c.ExamplesFor ""}}<h2>Package examples</h2>{{template "examples" .}}{{end}}
+
+<script>
+async function runExample(button) {
+  const result = button.nextElementSibling;
+  result.hidden = false;
+  result.textContent = "Running…";
+  const resp = await fetch(button.dataset.url, {method: "POST"});
+  result.textContent = await resp.text();
+}
+</script>
+{{template "footer"}}{{end}}
+
+{{define "examples"}}{{range .}}
+<h4>Example{{with .Suffix}} ({{.}}){{end}}</h4>
+{{if .Doc}}<p>{{.Doc}}</p>{{end}}
+<pre><code>{{.Source}}</code></pre>
+{{if or .Output .EmptyOutput}}<p>Output:</p>
+<pre class="output"><code>{{.Output}}</code></pre>{{end}}
+<button data-url="{{.RunURL}}" onclick="runExample(this)">Run</button>
+<pre class="result" hidden></pre>
+{{end}}{{end}}
+`))
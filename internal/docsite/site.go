@@ -0,0 +1,279 @@
+// Package docsite renders the chapters' package documentation and the
+// README as a small local website, with a button to run each example.
+package docsite
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"learn-go/internal/chapters"
+)
+
+// Site is everything the pages are rendered from. It is loaded fresh for
+// every request so edits show up on reload.
+type Site struct {
+	Chapters []*Chapter
+	Sections []Section // README sections, in order
+}
+
+// Chapter is one chapter's documentation.
+type Chapter struct {
+	chapters.Chapter
+	Package  *doc.Package
+	Fset     *token.FileSet
+	Examples []*Example
+}
+
+// Example is a runnable example.
+type Example struct {
+	*doc.Example
+	Func string // "ExampleAdd"
+	// Package is the package of the file declaring the example, which is
+	// "foo_test" for external tests.
+	Package string
+
+	chapter *Chapter
+}
+
+// Section is a README heading and the Markdown under it.
+type Section struct {
+	Level  int
+	Title  string
+	Anchor string
+	Body   string
+}
+
+// Load reads the chapters and README under root.
+func Load(root string) (*Site, error) {
+	all, err := chapters.Discover(root)
+	if err != nil {
+		return nil, err
+	}
+	module, err := chapters.ModulePath(root)
+	if err != nil {
+		return nil, err
+	}
+
+	site := &Site{}
+	for _, ch := range all {
+		c, err := loadChapter(module, ch)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ch.Name, err)
+		}
+		site.Chapters = append(site.Chapters, c)
+	}
+
+	readme, err := os.ReadFile(filepath.Join(root, "README.md"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	site.Sections = sections(string(readme))
+	return site, nil
+}
+
+func loadChapter(module string, ch chapters.Chapter) (*Chapter, error) {
+	fset := token.NewFileSet()
+	names, err := filepath.Glob(filepath.Join(ch.Dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	var files, tests []*ast.File
+	testPackages := map[string]string{}
+	for _, name := range names {
+		f, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasSuffix(name, "_test.go") {
+			files = append(files, f)
+			continue
+		}
+		tests = append(tests, f)
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && strings.HasPrefix(fn.Name.Name, "Example") {
+				testPackages[fn.Name.Name] = f.Name.Name
+			}
+		}
+	}
+
+	pkg, err := doc.NewFromFiles(fset, append(files, tests...), module+"/"+ch.Name)
+	if err != nil {
+		return nil, err
+	}
+	c := &Chapter{Chapter: ch, Package: pkg, Fset: fset}
+	for _, ex := range doc.Examples(tests...) {
+		name := "Example" + ex.Name
+		c.Examples = append(c.Examples, &Example{Example: ex, Func: name, Package: testPackages[name], chapter: c})
+	}
+	return c, nil
+}
+
+// Chapter returns the chapter called name, or nil.
+func (s *Site) Chapter(name string) *Chapter {
+	for _, c := range s.Chapters {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// Neighbours returns the chapters before and after c, or nil at the ends.
+func (s *Site) Neighbours(c *Chapter) (prev, next *Chapter) {
+	for i, other := range s.Chapters {
+		if other != c {
+			continue
+		}
+		if i > 0 {
+			prev = s.Chapters[i-1]
+		}
+		if i+1 < len(s.Chapters) {
+			next = s.Chapters[i+1]
+		}
+	}
+	return prev, next
+}
+
+// Related returns the README sections that are about c: its own entry in
+// the chapter list and any section naming one of its exported
+// identifiers in backticks.
+func (s *Site) Related(c *Chapter) []Section {
+	var names []string
+	for _, f := range c.Package.Funcs {
+		names = append(names, f.Name)
+	}
+	for _, t := range c.Package.Types {
+		names = append(names, t.Name)
+		for _, f := range t.Funcs {
+			names = append(names, f.Name)
+		}
+	}
+
+	var related []Section
+	for _, sec := range s.Sections {
+		if sec.Title == c.Name || mentionsAny(sec.Body, names) {
+			related = append(related, sec)
+		}
+	}
+	return related
+}
+
+func mentionsAny(markdown string, names []string) bool {
+	for _, name := range names {
+		if strings.Contains(markdown, "`"+name+"`") || strings.Contains(markdown, "`"+name+"(") {
+			return true
+		}
+	}
+	return false
+}
+
+// ExamplesFor returns the examples of the function or type name.
+func (c *Chapter) ExamplesFor(name string) []*Example {
+	var out []*Example
+	for _, ex := range c.Examples {
+		if ex.Name == name || strings.HasPrefix(ex.Name, name+"_") {
+			out = append(out, ex)
+		}
+	}
+	return out
+}
+
+// Decl formats a declaration without its body.
+func (c *Chapter) Decl(decl ast.Decl) string {
+	if fn, ok := decl.(*ast.FuncDecl); ok {
+		copied := *fn
+		copied.Body = nil
+		copied.Doc = nil
+		decl = &copied
+	}
+	return c.format(decl)
+}
+
+// Source formats the example's body.
+func (ex *Example) Source() string {
+	code := ex.chapter.format(ex.Code)
+	code = strings.TrimPrefix(code, "{\n")
+	code = strings.TrimSuffix(code, "}")
+	var lines []string
+	for _, line := range strings.Split(code, "\n") {
+		lines = append(lines, strings.TrimPrefix(line, "\t"))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func (c *Chapter) format(node any) string {
+	var buf bytes.Buffer
+	cfg := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	if err := cfg.Fprint(&buf, c.Fset, node); err != nil {
+		return err.Error()
+	}
+	return buf.String()
+}
+
+// RunURL is where the example is run from its page.
+func (ex *Example) RunURL() string {
+	return "/chapter/" + ex.chapter.Name + "/run/" + ex.Func
+}
+
+// sections splits a README into its "##" and "###" sections.
+func sections(readme string) []Section {
+	var (
+		out  []Section
+		cur  *Section
+		body []string
+		code bool
+		seen = map[string]int{}
+	)
+	flush := func() {
+		if cur != nil {
+			cur.Body = strings.TrimSpace(strings.Join(body, "\n"))
+			out = append(out, *cur)
+		}
+		body = nil
+	}
+	for _, line := range strings.Split(readme, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			code = !code
+		}
+		level := 0
+		if !code {
+			switch {
+			case strings.HasPrefix(line, "### "):
+				level = 3
+			case strings.HasPrefix(line, "## "):
+				level = 2
+			}
+		}
+		if level == 0 {
+			body = append(body, line)
+			continue
+		}
+		flush()
+		title := strings.TrimSpace(line[level+1:])
+		anchor := slug(title)
+		if n := seen[anchor]; n > 0 {
+			anchor = fmt.Sprintf("%s-%d", anchor, n)
+		}
+		seen[slug(title)]++
+		cur = &Section{Level: level, Title: title, Anchor: anchor}
+	}
+	flush()
+	return out
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slug turns "Errors, panic, defer" into "errors-panic-defer".
+func slug(title string) string {
+	return strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(title), "-"), "-")
+}
@@ -0,0 +1,124 @@
+// Package grapheme splits text into grapheme clusters, what readers see
+// as one character, by the rules of Unicode's UAX #29 (Unicode 15.1). A
+// flag, an emoji with a skin tone or a family joined with ZWJs, a letter
+// with combining accents, a Hangul syllable written as jamo and a
+// Devanagari conjunct are each one cluster, however many runes they
+// take, and cutting text anywhere but between clusters garbles it.
+package grapheme
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// First splits s into its first grapheme cluster and the rest.
+func First(s string) (cluster, rest string) {
+	if s == "" {
+		return "", ""
+	}
+	r, n := utf8.DecodeRuneInString(s)
+	prev := propertyOf(r)
+
+	// State for the rules that look further back than one rune.
+	var (
+		riCount      = 0     // regional indicators in a row (GB12, GB13)
+		emoji        = false // an Extended_Pictographic then Extend* (GB11)
+		emojiZWJ     = false // ... followed by a ZWJ
+		conjunct     = false // an InCB consonant then Extend/Linker* (GB9c)
+		conjunctJoin = false // ... with at least one linker
+	)
+	update := func(p property, r rune) {
+		if p == regionalIndicator {
+			riCount++
+		} else {
+			riCount = 0
+		}
+		switch {
+		case p == pictographic:
+			emoji, emojiZWJ = true, false
+		case emoji && !emojiZWJ && p == extend:
+		case emoji && p == zwj && !emojiZWJ:
+			emojiZWJ = true
+		default:
+			emoji, emojiZWJ = false, false
+		}
+		switch {
+		case unicode.Is(indicConsonants, r):
+			conjunct, conjunctJoin = true, false
+		case conjunct && unicode.Is(indicLinkers, r):
+			conjunctJoin = true
+		case conjunct && (p == extend || p == zwj):
+		default:
+			conjunct, conjunctJoin = false, false
+		}
+	}
+	update(prev, r)
+
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		p := propertyOf(r)
+		if breaksBetween(prev, p) {
+			switch {
+			case emojiZWJ && p == pictographic:
+				// GB11: ZWJ sequences are one emoji.
+			case prev == regionalIndicator && p == regionalIndicator && riCount%2 == 1:
+				// GB12, GB13: regional indicators pair up into flags.
+			case conjunctJoin && unicode.Is(indicConsonants, r):
+				// GB9c: a virama joins the consonants around it.
+			default:
+				return s[:n], s[n:]
+			}
+		}
+		update(p, r)
+		prev = p
+		n += size
+	}
+	return s, ""
+}
+
+// breaksBetween applies the rules of UAX #29 that only look at the runes
+// on either side of a boundary.
+func breaksBetween(prev, next property) bool {
+	switch {
+	case prev == cr && next == lf: // GB3
+		return false
+	case prev == cr || prev == lf || prev == control: // GB4
+		return true
+	case next == cr || next == lf || next == control: // GB5
+		return true
+	case prev == hangulL && (next == hangulL || next == hangulV || next == hangulLV || next == hangulLVT): // GB6
+		return false
+	case (prev == hangulLV || prev == hangulV) && (next == hangulV || next == hangulT): // GB7
+		return false
+	case (prev == hangulLVT || prev == hangulT) && next == hangulT: // GB8
+		return false
+	case next == extend || next == zwj: // GB9
+		return false
+	case next == spacingMark: // GB9a
+		return false
+	case prev == prepend: // GB9b
+		return false
+	}
+	return true // GB999, unless a rule with more context applies
+}
+
+// Clusters returns the grapheme clusters of s.
+func Clusters(s string) []string {
+	var clusters []string
+	for s != "" {
+		var c string
+		c, s = First(s)
+		clusters = append(clusters, c)
+	}
+	return clusters
+}
+
+// Count returns the number of grapheme clusters in s.
+func Count(s string) int {
+	n := 0
+	for s != "" {
+		_, s = First(s)
+		n++
+	}
+	return n
+}
@@ -0,0 +1,76 @@
+package grapheme
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestClusters(t *testing.T) {
+	// Clusters are separated by "|". Most cases come from Unicode's
+	// GraphemeBreakTest.txt.
+	cases := []struct {
+		name string
+		text string
+	}{
+		{"ASCII", "M|a|x"},
+		{"CRLF", "a|\r\n|b|\n|\r"},
+		{"control", "a|\u0000|\u0301"},
+		{"combining accents", "E\u0301|l|o\u0308\u0304|d|i|e"},
+		{"precomposed", "É|l|o|d|i|e"},
+		{"zero-width joiner after a letter", "a\u200d|b"},
+		{"Hangul jamo", "\u1100\u1161\u11a8|\uac01|\u1100"},
+		{"Hangul syllables", "한|국|어"},
+		{"Hangul LV then T", "각|각ᆨ|각"},
+		{"flags", "🇫🇷|🇯🇵|🇪"},
+		{"skin tone", "👋🏽|!"},
+		{"family", "👨\u200d👩\u200d👧\u200d👦|x"},
+		{"ZWJ without an emoji after", "👨\u200d|a"},
+		{"ZWJ after a letter doesn't join emoji", "a\u200d|👩"},
+		{"emoji presentation", "❤\ufe0f|a"},
+		{"keycap", "1\ufe0f\u20e3|2"},
+		{"tag sequence", "🏴\U000E0067\U000E0062\U000E0073\U000E0063\U000E0074\U000E007F|a"},
+		{"spacing mark", "कि|ता|ब"},
+		{"Devanagari conjunct", "न|म|स्ते"},
+		{"conjunct with ZWJ", "क्\u200dष|a"},
+		{"virama without a consonant after", "क्|a"},
+		{"Thai sara am", "กำ|ข"},
+		{"prepend", "\u0600١|a"},
+		{"prepend before a break", "\u0600|\n"},
+		{"soft hyphen", "a|\u00ad|b"},
+		{"Japanese", "こ|ん|に|ち|は"},
+		{"Arabic", "م|ر|ح|ب|ا"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			want := strings.Split(c.text, "|")
+			got := Clusters(strings.ReplaceAll(c.text, "|", ""))
+			if !slices.Equal(got, want) {
+				t.Errorf("got %+q want %+q", got, want)
+			}
+			if n := Count(strings.ReplaceAll(c.text, "|", "")); n != len(want) {
+				t.Errorf("Count: got %d want %d", n, len(want))
+			}
+		})
+	}
+}
+
+func TestFirst(t *testing.T) {
+	if c, rest := First(""); c != "" || rest != "" {
+		t.Errorf("got %q, %q", c, rest)
+	}
+	if c, rest := First("é!"); c != "é" || rest != "!" {
+		t.Errorf("got %q, %q", c, rest)
+	}
+	// Invalid UTF-8 is one cluster per byte.
+	if got := Clusters("a\xffb"); !slices.Equal(got, []string{"a", "\xff", "b"}) {
+		t.Errorf("got %q", got)
+	}
+}
+
+func BenchmarkClusters(b *testing.B) {
+	text := strings.Repeat("Élodie 👋🏽 नमस्ते 🇫🇷 ", 10)
+	for b.Loop() {
+		Count(text)
+	}
+}
@@ -0,0 +1,141 @@
+package grapheme
+
+import "unicode"
+
+// property is a rune's Grapheme_Cluster_Break property, from UAX #29,
+// with Extended_Pictographic folded in since the rules treat it the same
+// way.
+type property int
+
+const (
+	other property = iota
+	cr
+	lf
+	control
+	extend
+	zwj
+	regionalIndicator
+	prepend
+	spacingMark
+	hangulL
+	hangulV
+	hangulT
+	hangulLV
+	hangulLVT
+	pictographic
+)
+
+// The ranges below are the parts of the Unicode 15.1 property files that
+// aren't already a general category in package unicode.
+var (
+	prependTable = &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{0x0600, 0x0605, 1}, {0x06dd, 0x06dd, 1}, {0x070f, 0x070f, 1},
+			{0x0890, 0x0891, 1}, {0x08e2, 0x08e2, 1}, {0x0d4e, 0x0d4e, 1},
+		},
+		R32: []unicode.Range32{
+			{0x110bd, 0x110bd, 1}, {0x110cd, 0x110cd, 1}, {0x111c2, 0x111c3, 1},
+			{0x1193f, 0x1193f, 1}, {0x11941, 0x11941, 1}, {0x11a3a, 0x11a3a, 1},
+			{0x11a84, 0x11a89, 1}, {0x11d46, 0x11d46, 1}, {0x11f02, 0x11f02, 1},
+		},
+	}
+
+	pictographicTable = &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{0x00a9, 0x00ae, 5}, {0x203c, 0x2049, 13}, {0x2122, 0x2139, 23},
+			{0x2194, 0x2199, 1}, {0x21a9, 0x21aa, 1}, {0x231a, 0x231b, 1},
+			{0x2328, 0x2388, 96}, {0x23cf, 0x23cf, 1}, {0x23e9, 0x23f3, 1},
+			{0x23f8, 0x23fa, 1}, {0x24c2, 0x24c2, 1}, {0x25aa, 0x25ab, 1},
+			{0x25b6, 0x25c0, 10}, {0x25fb, 0x25fe, 1}, {0x2600, 0x2605, 1},
+			{0x2607, 0x2612, 1}, {0x2614, 0x2685, 1}, {0x2690, 0x2705, 1},
+			{0x2708, 0x2712, 1}, {0x2714, 0x2716, 2}, {0x271d, 0x2721, 4},
+			{0x2728, 0x2728, 1}, {0x2733, 0x2734, 1}, {0x2744, 0x2747, 3},
+			{0x274c, 0x274e, 2}, {0x2753, 0x2755, 1}, {0x2757, 0x2757, 1},
+			{0x2763, 0x2767, 1}, {0x2795, 0x2797, 1}, {0x27a1, 0x27b0, 15},
+			{0x27bf, 0x27bf, 1}, {0x2934, 0x2935, 1}, {0x2b05, 0x2b07, 1},
+			{0x2b1b, 0x2b1c, 1}, {0x2b50, 0x2b55, 5}, {0x3030, 0x303d, 13},
+			{0x3297, 0x3299, 2},
+		},
+		R32: []unicode.Range32{
+			{0x1f000, 0x1f0ff, 1}, {0x1f10d, 0x1f10f, 1}, {0x1f12f, 0x1f12f, 1},
+			{0x1f16c, 0x1f171, 1}, {0x1f17e, 0x1f17f, 1}, {0x1f18e, 0x1f18e, 1},
+			{0x1f191, 0x1f19a, 1}, {0x1f1ad, 0x1f1e5, 1}, {0x1f201, 0x1f20f, 1},
+			{0x1f21a, 0x1f22f, 21}, {0x1f232, 0x1f23a, 1}, {0x1f23c, 0x1f23f, 1},
+			{0x1f249, 0x1f3fa, 1}, {0x1f400, 0x1f53d, 1}, {0x1f546, 0x1f64f, 1},
+			{0x1f680, 0x1f6ff, 1}, {0x1f774, 0x1f77f, 1}, {0x1f7d5, 0x1f7ff, 1},
+			{0x1f80c, 0x1f80f, 1}, {0x1f848, 0x1f84f, 1}, {0x1f85a, 0x1f85f, 1},
+			{0x1f888, 0x1f88f, 1}, {0x1f8ae, 0x1f8ff, 1}, {0x1f90c, 0x1f93a, 1},
+			{0x1f93c, 0x1f945, 1}, {0x1f947, 0x1faff, 1}, {0x1fc00, 0x1fffd, 1},
+		},
+	}
+
+	// indicConsonants and indicLinkers are the InCB=Consonant and
+	// InCB=Linker runes: the consonants and viramas of the scripts where
+	// a virama joins two consonants into one conjunct.
+	indicConsonants = &unicode.RangeTable{
+		R16: []unicode.Range16{
+			// Devanagari
+			{0x0915, 0x0939, 1}, {0x0958, 0x095f, 1}, {0x0978, 0x097f, 1},
+			// Bengali
+			{0x0995, 0x09a8, 1}, {0x09aa, 0x09b0, 1}, {0x09b2, 0x09b2, 1},
+			{0x09b6, 0x09b9, 1}, {0x09dc, 0x09dd, 1}, {0x09df, 0x09df, 1},
+			{0x09f0, 0x09f1, 1},
+			// Gujarati
+			{0x0a95, 0x0aa8, 1}, {0x0aaa, 0x0ab0, 1}, {0x0ab2, 0x0ab3, 1},
+			{0x0ab5, 0x0ab9, 1}, {0x0af9, 0x0af9, 1},
+			// Oriya
+			{0x0b15, 0x0b28, 1}, {0x0b2a, 0x0b30, 1}, {0x0b32, 0x0b33, 1},
+			{0x0b35, 0x0b39, 1}, {0x0b5c, 0x0b5d, 1}, {0x0b5f, 0x0b5f, 1},
+			{0x0b71, 0x0b71, 1},
+			// Telugu
+			{0x0c15, 0x0c28, 1}, {0x0c2a, 0x0c39, 1}, {0x0c58, 0x0c5a, 1},
+			// Malayalam
+			{0x0d15, 0x0d3a, 1},
+		},
+	}
+	indicLinkers = &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{0x094d, 0x09cd, 0x80}, {0x0acd, 0x0b4d, 0x80}, {0x0c4d, 0x0d4d, 0x100},
+		},
+	}
+)
+
+func propertyOf(r rune) property {
+	switch {
+	case r == '\r':
+		return cr
+	case r == '\n':
+		return lf
+	case r == 0x200d:
+		return zwj
+	case r == 0x200c:
+		return extend
+	case r >= 0x1f1e6 && r <= 0x1f1ff:
+		return regionalIndicator
+	// Emoji skin tone modifiers and tags are Extend.
+	case r >= 0x1f3fb && r <= 0x1f3ff, r >= 0xe0020 && r <= 0xe007f:
+		return extend
+	case r >= 0x1100 && r <= 0x115f, r >= 0xa960 && r <= 0xa97c:
+		return hangulL
+	case r >= 0x1160 && r <= 0x11a7, r >= 0xd7b0 && r <= 0xd7c6:
+		return hangulV
+	case r >= 0x11a8 && r <= 0x11ff, r >= 0xd7cb && r <= 0xd7fb:
+		return hangulT
+	case r >= 0xac00 && r <= 0xd7a3:
+		if (r-0xac00)%28 == 0 {
+			return hangulLV
+		}
+		return hangulLVT
+	case unicode.Is(prependTable, r):
+		return prepend
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Other_Grapheme_Extend):
+		return extend
+	case r == 0x0e33 || r == 0x0eb3 || unicode.Is(unicode.Mc, r):
+		return spacingMark
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp) || r >= 0xfff0 && r <= 0xfffb:
+		return control
+	case unicode.Is(pictographicTable, r):
+		return pictographic
+	}
+	return other
+}
@@ -0,0 +1,141 @@
+// Package hints turns failing chapter tests into hints. Each chapter
+// lists its hints in a hints.json file, keyed by test name, and they are
+// revealed one at a time so learners get a nudge before the answer.
+package hints
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"learn-go/internal/chapters"
+)
+
+// File is the name of the hints file in a chapter directory.
+const File = "hints.json"
+
+// Rule maps a failing test to hints, vaguest first.
+type Rule struct {
+	// Test is a test name such as "TestHello/in_Spanish". It also
+	// matches the test's subtests.
+	Test string `json:"test"`
+	// Match, if set, is a regular expression the failure output must
+	// match too, to tell apart different ways a test can fail.
+	Match string   `json:"match,omitempty"`
+	Hints []string `json:"hints"`
+
+	match *regexp.Regexp
+}
+
+// Load reads the rules of the chapter in dir. A chapter without a hints
+// file has no rules.
+func Load(dir string) ([]Rule, error) {
+	data, err := os.ReadFile(filepath.Join(dir, File))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("%s: %w", File, err)
+	}
+	for i := range rules {
+		if rules[i].Test == "" || len(rules[i].Hints) == 0 {
+			return nil, fmt.Errorf("%s: rule %d needs a test and hints", File, i+1)
+		}
+		if rules[i].Match != "" {
+			if rules[i].match, err = regexp.Compile(rules[i].Match); err != nil {
+				return nil, fmt.Errorf("%s: rule for %s: %w", File, rules[i].Test, err)
+			}
+		}
+	}
+	return rules, nil
+}
+
+// For returns the rule for the first failing test of r that has one.
+// Subtests fail before their parents, so the most specific rule wins.
+func For(rules []Rule, r chapters.Result) (Rule, bool) {
+	for _, test := range r.Failed {
+		for _, rule := range rules {
+			if test != rule.Test && !strings.HasPrefix(test, rule.Test+"/") {
+				continue
+			}
+			if rule.match != nil && !rule.match.MatchString(r.Output) {
+				continue
+			}
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// key identifies a rule across runs.
+func (rule Rule) key(chapter string) string {
+	return chapter + " " + rule.Test + " " + rule.Match
+}
+
+// StateFile is where revealed hints are counted, relative to the module
+// root.
+const StateFile = ".learn/hints.json"
+
+// State counts how many hints of each rule have been revealed.
+type State struct {
+	Revealed map[string]int `json:"revealed"`
+}
+
+// LoadState reads the state saved under root.
+func LoadState(root string) (*State, error) {
+	s := &State{Revealed: map[string]int{}}
+	data, err := os.ReadFile(filepath.Join(root, StateFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("load hints: %w", err)
+	}
+	if s.Revealed == nil {
+		s.Revealed = map[string]int{}
+	}
+	return s, nil
+}
+
+// Save writes s under root.
+func (s *State) Save(root string) error {
+	path := filepath.Join(root, StateFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Reveal reveals one more hint of rule and returns every hint revealed
+// so far.
+func (s *State) Reveal(chapter string, rule Rule) []string {
+	key := rule.key(chapter)
+	n := min(s.Revealed[key]+1, len(rule.Hints))
+	s.Revealed[key] = n
+	return rule.Hints[:n]
+}
+
+// Reset forgets the hints revealed for chapter, once it passes.
+func (s *State) Reset(chapter string) {
+	for key := range s.Revealed {
+		if strings.HasPrefix(key, chapter+" ") {
+			delete(s.Revealed, key)
+		}
+	}
+}
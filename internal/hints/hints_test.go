@@ -0,0 +1,141 @@
+package hints
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"learn-go/internal/chapters"
+)
+
+const rules = `[
+  {"test": "TestHello/in_Spanish", "hints": ["one", "two"]},
+  {"test": "TestHello", "match": "World", "hints": ["empty name"]},
+  {"test": "TestHello", "hints": ["generic"]}
+]`
+
+func TestFor(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, File), []byte(rules), 0o644)
+	rs, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		result chapters.Result
+		want   string
+	}{
+		{"subtest rule", chapters.Result{Failed: []string{"TestHello/in_Spanish", "TestHello"}}, "one"},
+		{"matching output", chapters.Result{Failed: []string{"TestHello/empty"}, Output: `want "Hello, World"`}, "empty name"},
+		{"parent rule", chapters.Result{Failed: []string{"TestHello/in_French", "TestHello"}}, "generic"},
+		{"no rule", chapters.Result{Failed: []string{"TestAdder"}}, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rule, ok := For(rs, c.result)
+			got := ""
+			if ok {
+				got = rule.Hints[0]
+			}
+			if got != c.want {
+				t.Errorf("got first hint %q want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestReveal(t *testing.T) {
+	root := t.TempDir()
+	rule := Rule{Test: "TestAdder", Hints: []string{"one", "two"}}
+
+	s, _ := LoadState(root)
+	s.Reveal("02-integers", rule)
+	s.Save(root)
+
+	s, err := LoadState(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"one,two", "one,two"} {
+		if got := strings.Join(s.Reveal("02-integers", rule), ","); got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	s.Reset("02-integers")
+	if got := s.Reveal("02-integers", rule); len(got) != 1 {
+		t.Errorf("got %q after a reset", got)
+	}
+}
+
+// TestChapterHints checks that the chapters' hints point at real tests.
+func TestChapterHints(t *testing.T) {
+	all, err := chapters.Discover("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ch := range all {
+		rs, err := Load(ch.Dir)
+		if err != nil {
+			t.Errorf("%s: %v", ch.Name, err)
+		}
+		tests := ""
+		names, _ := filepath.Glob(filepath.Join(ch.Dir, "*_test.go"))
+		for _, name := range names {
+			src, _ := os.ReadFile(name)
+			tests += string(src)
+		}
+		for _, r := range rs {
+			top := strings.SplitN(r.Test, "/", 2)[0]
+			if !strings.Contains(tests, "func "+top+"(") {
+				t.Errorf("%s: hints for %s, which doesn't exist", ch.Name, r.Test)
+			}
+		}
+	}
+}
+
+func TestMistakes(t *testing.T) {
+	dir := t.TempDir()
+	src := `package chapter
+
+func Add(a, b int) int {
+	return 4
+}
+
+func Hello(name, language string) string {
+	return "Hello, " + name
+}
+
+func Sub(a, b int) int {
+	return a - b
+}
+
+func unexported(x int) int { return 1 }
+`
+	os.WriteFile(filepath.Join(dir, "chapter.go"), []byte(src), 0o644)
+	os.WriteFile(filepath.Join(dir, "chapter_test.go"), []byte("package chapter\n\nfunc Mul(a int) int { return 0 }\n"), 0o644)
+
+	found, err := Mistakes(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range found {
+		got = append(got, m.Message)
+	}
+	want := []string{
+		"Add always returns 4 whatever its arguments are; compute the result from a and b",
+		"Hello never uses its language parameter",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %q want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got %q want %q", got[i], want[i])
+		}
+	}
+}
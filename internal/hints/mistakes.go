@@ -0,0 +1,114 @@
+package hints
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// Mistake is a common mistake spotted in a learner's code.
+type Mistake struct {
+	Pos     token.Position
+	Message string
+}
+
+func (m Mistake) String() string { return fmt.Sprintf("%s: %s", m.Pos, m.Message) }
+
+// Mistakes looks for common mistakes in the non-test files of dir:
+//
+//   - a function with parameters that returns only literals, like
+//     `return 4` in Add, which passes one test but isn't the real thing
+//   - a parameter the function never uses, like a Hello that ignores
+//     language
+func Mistakes(dir string) ([]Mistake, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	var found []Mistake
+	fset := token.NewFileSet()
+	for _, name := range names {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil || !fn.Name.IsExported() {
+				continue
+			}
+			params := paramNames(fn)
+			if len(params) == 0 {
+				continue
+			}
+			if lit, ok := hardCoded(fn); ok {
+				found = append(found, Mistake{
+					Pos:     fset.Position(fn.Pos()),
+					Message: fmt.Sprintf("%s always returns %s whatever its arguments are; compute the result from %s", fn.Name.Name, lit, strings.Join(params, " and ")),
+				})
+				continue
+			}
+			used := usedNames(fn.Body)
+			for _, p := range params {
+				if !used[p] {
+					found = append(found, Mistake{
+						Pos:     fset.Position(fn.Pos()),
+						Message: fmt.Sprintf("%s never uses its %s parameter", fn.Name.Name, p),
+					})
+				}
+			}
+		}
+	}
+	return found, nil
+}
+
+func paramNames(fn *ast.FuncDecl) []string {
+	var names []string
+	for _, field := range fn.Type.Params.List {
+		for _, name := range field.Names {
+			if name.Name != "_" {
+				names = append(names, name.Name)
+			}
+		}
+	}
+	return names
+}
+
+// hardCoded reports whether fn's body is a single return of literals,
+// and returns them as written.
+func hardCoded(fn *ast.FuncDecl) (string, bool) {
+	if len(fn.Body.List) != 1 {
+		return "", false
+	}
+	ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) == 0 {
+		return "", false
+	}
+	var lits []string
+	for _, r := range ret.Results {
+		lit, ok := r.(*ast.BasicLit)
+		if !ok {
+			return "", false
+		}
+		lits = append(lits, lit.Value)
+	}
+	return strings.Join(lits, ", "), true
+}
+
+func usedNames(body *ast.BlockStmt) map[string]bool {
+	used := map[string]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			used[id.Name] = true
+		}
+		return true
+	})
+	return used
+}
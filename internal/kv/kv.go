@@ -0,0 +1,364 @@
+// Package kv is a small embedded key-value store: an append-only log on
+// disk and an index in memory of where each key's latest value is.
+//
+// Every record carries a CRC. Open replays the log and, at the first
+// record that is cut short or fails its CRC, truncates the file: with an
+// append-only log that can only be a write torn by a crash, and
+// everything before it is intact. Overwritten and deleted values stay in
+// the log until Compact rewrites it with only the live ones.
+package kv
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Limits on record sizes, which also keep a corrupt length from
+// allocating gigabytes during recovery.
+const (
+	MaxKey   = 1 << 10
+	MaxValue = 1 << 20
+)
+
+// ErrClosed is returned by operations on a closed DB.
+var ErrClosed = errors.New("kv: closed")
+
+// A record on disk is a header followed by the key and the value:
+//
+//	crc    uint32  CRC-32C of everything after it
+//	kind   uint8   put or del
+//	keyLen uint32
+//	valLen uint32
+const headerSize = 4 + 1 + 4 + 4
+
+const (
+	put byte = 1
+	del byte = 2
+)
+
+var table = crc32.MakeTable(crc32.Castagnoli)
+
+// entry is where a key's value is in the log.
+type entry struct {
+	offset int64 // of the value
+	size   int
+}
+
+// DB is an open store. It is safe for concurrent use: readers share a
+// lock, writers take it alone.
+type DB struct {
+	path string
+	sync bool
+
+	mu      sync.RWMutex
+	f       *os.File
+	size    int64 // bytes in the log
+	index   map[string]entry
+	garbage int64 // bytes of records a later one replaced
+}
+
+// Options configure a DB.
+type Options struct {
+	// NoSync skips the fsync after each write. Writes are faster but the
+	// last ones can be lost if the machine, not just the process, dies.
+	NoSync bool
+}
+
+// Open opens the store in the file at path, creating it if needed, and
+// recovers from a crash that tore the last write.
+func Open(path string, opts Options) (*DB, error) {
+	// A compaction the process didn't finish left its new log behind;
+	// the old one is still whole.
+	os.Remove(path + ".compact")
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	db := &DB{path: path, sync: !opts.NoSync, f: f, index: map[string]entry{}}
+	if err := db.replay(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// replay rebuilds the index from the log and cuts off a torn tail.
+func (db *DB) replay() error {
+	r := bufio.NewReader(io.NewSectionReader(db.f, 0, 1<<62))
+	var offset int64
+	for {
+		kind, key, value, n, err := readRecord(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Torn or corrupt: keep everything before this record.
+			if err := db.f.Truncate(offset); err != nil {
+				return fmt.Errorf("kv: truncating torn write: %w", err)
+			}
+			break
+		}
+		if old, ok := db.index[key]; ok {
+			db.garbage += recordSize(key, old.size)
+		}
+		switch kind {
+		case put:
+			db.index[key] = entry{offset: offset + headerSize + int64(len(key)), size: len(value)}
+		case del:
+			delete(db.index, key)
+			db.garbage += n
+		}
+		offset += n
+	}
+	db.size = offset
+	return nil
+}
+
+var errCorrupt = errors.New("kv: corrupt record")
+
+// readRecord reads one record and returns its size. A record cut short
+// or failing its CRC is errCorrupt; a clean end of the log is io.EOF.
+func readRecord(r io.Reader) (kind byte, key string, value []byte, n int64, err error) {
+	var h [headerSize]byte
+	if _, err := io.ReadFull(r, h[:]); err != nil {
+		if err == io.EOF {
+			return 0, "", nil, 0, io.EOF
+		}
+		return 0, "", nil, 0, errCorrupt
+	}
+	kind = h[4]
+	keyLen := binary.LittleEndian.Uint32(h[5:])
+	valLen := binary.LittleEndian.Uint32(h[9:])
+	if (kind != put && kind != del) || keyLen > MaxKey || valLen > MaxValue {
+		return 0, "", nil, 0, errCorrupt
+	}
+	body := make([]byte, keyLen+valLen)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, "", nil, 0, errCorrupt
+	}
+	crc := crc32.Update(crc32.Checksum(h[4:], table), table, body)
+	if crc != binary.LittleEndian.Uint32(h[:4]) {
+		return 0, "", nil, 0, errCorrupt
+	}
+	return kind, string(body[:keyLen]), body[keyLen:], int64(headerSize + len(body)), nil
+}
+
+func encodeRecord(kind byte, key string, value []byte) []byte {
+	b := make([]byte, headerSize, headerSize+len(key)+len(value))
+	b[4] = kind
+	binary.LittleEndian.PutUint32(b[5:], uint32(len(key)))
+	binary.LittleEndian.PutUint32(b[9:], uint32(len(value)))
+	b = append(b, key...)
+	b = append(b, value...)
+	binary.LittleEndian.PutUint32(b, crc32.Checksum(b[4:], table))
+	return b
+}
+
+func recordSize(key string, valueSize int) int64 {
+	return int64(headerSize + len(key) + valueSize)
+}
+
+// Get returns the value of key and whether it has one.
+func (db *DB) Get(key string) ([]byte, bool, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	if db.f == nil {
+		return nil, false, ErrClosed
+	}
+	e, ok := db.index[key]
+	if !ok {
+		return nil, false, nil
+	}
+	value, err := db.read(e)
+	return value, err == nil, err
+}
+
+// read reads the value at e. ReadAt doesn't move the file offset, so
+// readers holding the read lock can call it at once.
+func (db *DB) read(e entry) ([]byte, error) {
+	value := make([]byte, e.size)
+	if _, err := db.f.ReadAt(value, e.offset); err != nil {
+		return nil, fmt.Errorf("kv: %w", err)
+	}
+	return value, nil
+}
+
+// Put sets the value of key.
+func (db *DB) Put(key string, value []byte) error {
+	if key == "" || len(key) > MaxKey {
+		return fmt.Errorf("kv: key must be 1 to %d bytes", MaxKey)
+	}
+	if len(value) > MaxValue {
+		return fmt.Errorf("kv: value over %d bytes", MaxValue)
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.append(put, key, value)
+}
+
+// Delete removes key. Deleting a missing key is not an error.
+func (db *DB) Delete(key string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if _, ok := db.index[key]; !ok {
+		return nil
+	}
+	return db.append(del, key, nil)
+}
+
+// append writes a record at the end of the log and indexes it. The
+// caller holds the write lock.
+func (db *DB) append(kind byte, key string, value []byte) error {
+	if db.f == nil {
+		return ErrClosed
+	}
+	rec := encodeRecord(kind, key, value)
+	if _, err := db.f.WriteAt(rec, db.size); err != nil {
+		// Nothing points at whatever part of the record made it: the
+		// next write goes over it, or the next Open cuts it off.
+		return fmt.Errorf("kv: %w", err)
+	}
+	if db.sync {
+		if err := db.f.Sync(); err != nil {
+			return fmt.Errorf("kv: %w", err)
+		}
+	}
+
+	if old, ok := db.index[key]; ok {
+		db.garbage += recordSize(key, old.size)
+	}
+	if kind == put {
+		db.index[key] = entry{offset: db.size + headerSize + int64(len(key)), size: len(value)}
+	} else {
+		delete(db.index, key)
+		db.garbage += int64(len(rec))
+	}
+	db.size += int64(len(rec))
+	return nil
+}
+
+// Scan calls fn for each key starting with prefix, in key order, with a
+// snapshot of the values taken when Scan was called. fn may use the DB.
+// Scan stops at the first error fn returns and returns it.
+func (db *DB) Scan(prefix string, fn func(key string, value []byte) error) error {
+	type kv struct {
+		key   string
+		value []byte
+	}
+	var snapshot []kv
+
+	db.mu.RLock()
+	if db.f == nil {
+		db.mu.RUnlock()
+		return ErrClosed
+	}
+	for key, e := range db.index {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		value, err := db.read(e)
+		if err != nil {
+			db.mu.RUnlock()
+			return err
+		}
+		snapshot = append(snapshot, kv{key, value})
+	}
+	db.mu.RUnlock()
+
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].key < snapshot[j].key })
+	for _, p := range snapshot {
+		if err := fn(p.key, p.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stats describes the log.
+type Stats struct {
+	Keys    int
+	Size    int64 // bytes in the log
+	Garbage int64 // bytes Compact would reclaim
+}
+
+// Stats returns the current size of the log and how much of it is stale.
+func (db *DB) Stats() Stats {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return Stats{Keys: len(db.index), Size: db.size, Garbage: db.garbage}
+}
+
+// Compact rewrites the log with only the live values. The new log is
+// written beside the old one and renamed over it, so a crash during
+// compaction leaves the old log in place.
+func (db *DB) Compact() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.f == nil {
+		return ErrClosed
+	}
+
+	tmp := db.path + ".compact"
+	f, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("kv: %w", err)
+	}
+	keys := make([]string, 0, len(db.index))
+	for key := range db.index {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	index := make(map[string]entry, len(keys))
+	w := bufio.NewWriter(f)
+	var size int64
+	for _, key := range keys {
+		value, err := db.read(db.index[key])
+		if err != nil {
+			f.Close()
+			os.Remove(tmp)
+			return err
+		}
+		rec := encodeRecord(put, key, value)
+		w.Write(rec)
+		index[key] = entry{offset: size + headerSize + int64(len(key)), size: len(value)}
+		size += int64(len(rec))
+	}
+	err = w.Flush()
+	if err == nil {
+		err = f.Sync()
+	}
+	if err == nil {
+		err = os.Rename(tmp, db.path)
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("kv: compacting: %w", err)
+	}
+
+	db.f.Close()
+	db.f, db.index, db.size, db.garbage = f, index, size, 0
+	return nil
+}
+
+// Close closes the log. Reads and writes after Close fail with ErrClosed.
+func (db *DB) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.f == nil {
+		return ErrClosed
+	}
+	err := db.f.Close()
+	db.f = nil
+	return err
+}
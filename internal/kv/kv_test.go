@@ -0,0 +1,222 @@
+package kv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func open(t testing.TB, path string) *DB {
+	t.Helper()
+	db, err := Open(path, Options{NoSync: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func assertValue(t testing.TB, db *DB, key, want string, wantOK bool) {
+	t.Helper()
+	got, ok, err := db.Get(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want || ok != wantOK {
+		t.Errorf("%s: got %q, %v want %q, %v", key, got, ok, want, wantOK)
+	}
+}
+
+func TestPutGetDelete(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "greetings.kv")
+	db := open(t, path)
+
+	db.Put("lang/max", []byte("English"))
+	db.Put("lang/elodie", []byte("French"))
+	db.Put("lang/elodie", []byte("Spanish"))
+	db.Put("prefix/Klingon", []byte("nuqneH, "))
+	db.Delete("prefix/Klingon")
+	db.Delete("never/there")
+
+	check := func(db *DB) {
+		assertValue(t, db, "lang/max", "English", true)
+		assertValue(t, db, "lang/elodie", "Spanish", true)
+		assertValue(t, db, "prefix/Klingon", "", false)
+	}
+	check(db)
+
+	db.Close()
+	if _, _, err := db.Get("lang/max"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Get after Close: got %v", err)
+	}
+	db = open(t, path)
+	check(db)
+	if s := db.Stats(); s.Keys != 2 || s.Garbage == 0 {
+		t.Errorf("got stats %+v", s)
+	}
+
+	if err := db.Put("", []byte("x")); err == nil {
+		t.Error("put an empty key")
+	}
+	if err := db.Put("big", make([]byte, MaxValue+1)); err == nil {
+		t.Error("put a value over MaxValue")
+	}
+}
+
+func TestScan(t *testing.T) {
+	db := open(t, filepath.Join(t.TempDir(), "db"))
+	for _, k := range []string{"prefix/French", "lang/max", "prefix/English", "prefix/Spanish", "prefixes"} {
+		db.Put(k, []byte(strings.ToUpper(k)))
+	}
+	db.Delete("prefix/Spanish")
+
+	var got []string
+	err := db.Scan("prefix/", func(key string, value []byte) error {
+		got = append(got, key+"="+string(value))
+		// Writing during a scan is fine: Scan works on a snapshot.
+		return db.Put("seen/"+key, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, " ") != "prefix/English=PREFIX/ENGLISH prefix/French=PREFIX/FRENCH" {
+		t.Errorf("got %q", got)
+	}
+
+	stop := errors.New("stop")
+	n := 0
+	err = db.Scan("", func(string, []byte) error { n++; return stop })
+	if err != stop || n != 1 {
+		t.Errorf("got %v after %d keys", err, n)
+	}
+}
+
+// writeLog writes n records and returns the size of the log after each.
+func writeLog(t testing.TB, path string, n int) []int64 {
+	t.Helper()
+	db := open(t, path)
+	var sizes []int64
+	for i := range n {
+		db.Put(fmt.Sprintf("key%d", i), []byte(strings.Repeat("v", 10*i)))
+		sizes = append(sizes, db.Stats().Size)
+	}
+	db.Close()
+	return sizes
+}
+
+// TestTornWrite simulates a crash at every byte of the last write.
+func TestTornWrite(t *testing.T) {
+	dir := t.TempDir()
+	full := filepath.Join(dir, "full")
+	sizes := writeLog(t, full, 3)
+	log, _ := os.ReadFile(full)
+
+	for cut := sizes[1]; cut < sizes[2]; cut++ {
+		path := filepath.Join(dir, fmt.Sprintf("cut%d", cut))
+		os.WriteFile(path, log[:cut], 0o644)
+
+		db := open(t, path)
+		assertValue(t, db, "key0", "", true)
+		assertValue(t, db, "key1", strings.Repeat("v", 10), true)
+		assertValue(t, db, "key2", "", false)
+		if info, _ := os.Stat(path); info.Size() != sizes[1] {
+			t.Fatalf("cut at %d: log is %d bytes, want the torn write truncated to %d", cut, info.Size(), sizes[1])
+		}
+
+		// The store carries on where the intact log ends.
+		db.Put("key2", []byte("again"))
+		db.Close()
+		db = open(t, path)
+		assertValue(t, db, "key2", "again", true)
+	}
+}
+
+func TestCorruptRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db")
+	sizes := writeLog(t, path, 3)
+
+	// Flip a bit in the middle record's value: it and everything after
+	// it go.
+	log, _ := os.ReadFile(path)
+	log[sizes[1]-1] ^= 0x10
+	os.WriteFile(path, log, 0o644)
+
+	db := open(t, path)
+	assertValue(t, db, "key0", "", true)
+	assertValue(t, db, "key1", "", false)
+	assertValue(t, db, "key2", "", false)
+	if s := db.Stats(); s.Size != sizes[0] {
+		t.Errorf("log is %d bytes want %d", s.Size, sizes[0])
+	}
+}
+
+func TestCompact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db")
+	db := open(t, path)
+	for i := range 100 {
+		db.Put("counter", []byte(fmt.Sprint(i)))
+		db.Put(fmt.Sprintf("tmp%d", i), []byte("x"))
+		db.Delete(fmt.Sprintf("tmp%d", i))
+	}
+	db.Put("name", []byte("Elodie"))
+	before := db.Stats()
+
+	if err := db.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	after := db.Stats()
+	if after.Keys != 2 || after.Garbage != 0 || after.Size >= before.Size-before.Garbage+1 {
+		t.Errorf("before %+v after %+v", before, after)
+	}
+	assertValue(t, db, "counter", "99", true)
+
+	// Writes after compaction go to the new log.
+	db.Put("name", []byte("Max"))
+	db.Close()
+	db = open(t, path)
+	assertValue(t, db, "counter", "99", true)
+	assertValue(t, db, "name", "Max", true)
+
+	t.Run("crash during compaction", func(t *testing.T) {
+		// A half-written new log is left behind; the old one is whole.
+		db.Close()
+		os.WriteFile(path+".compact", []byte("half a log"), 0o644)
+		db := open(t, path)
+		assertValue(t, db, "name", "Max", true)
+		if _, err := os.Stat(path + ".compact"); !os.IsNotExist(err) {
+			t.Errorf("leftover compaction log not removed: %v", err)
+		}
+	})
+}
+
+func TestConcurrentReaders(t *testing.T) {
+	db := open(t, filepath.Join(t.TempDir(), "db"))
+	db.Put("greeting", []byte("Hello, "))
+
+	var wg sync.WaitGroup
+	for r := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				v, ok, err := db.Get("greeting")
+				if err != nil || !ok || !strings.HasSuffix(string(v), ", ") {
+					t.Errorf("reader %d: got %q, %v, %v", r, v, ok, err)
+					return
+				}
+				db.Scan("", func(string, []byte) error { return nil })
+			}
+		}()
+	}
+	for i := range 200 {
+		db.Put("greeting", []byte([]string{"Hola, ", "Bonjour, "}[i%2]))
+		if i%50 == 0 {
+			db.Compact()
+		}
+	}
+	wg.Wait()
+}
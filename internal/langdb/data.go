@@ -0,0 +1,91 @@
+package langdb
+
+// languages are the languages the greetings are in, in English
+// alphabetical order. For macrolanguages the ISO 639-3 code is the
+// macrolanguage's; the codes of the individual language commonly meant
+// are aliases.
+var languages = []Language{
+	{Name: "Amharic", Autonym: "አማርኛ", ISO6391: "am", ISO6392: "amh", ISO6393: "amh"},
+	{Name: "Arabic", Autonym: "العربية", ISO6391: "ar", ISO6392: "ara", ISO6393: "ara", Aliases: []string{"arb", "Modern Standard Arabic"}},
+	{Name: "Bengali", Autonym: "বাংলা", ISO6391: "bn", ISO6392: "ben", ISO6393: "ben", Aliases: []string{"Bangla"}},
+	{Name: "Catalan", Autonym: "català", ISO6391: "ca", ISO6392: "cat", ISO6393: "cat", Aliases: []string{"Valencian", "valencià"}},
+	{Name: "Chinese", Autonym: "中文", ISO6391: "zh", ISO6392: "zho", ISO6392B: "chi", ISO6393: "zho", Aliases: []string{"cmn", "Mandarin", "汉语", "漢語", "普通话", "國語"}},
+	{Name: "Czech", Autonym: "čeština", ISO6391: "cs", ISO6392: "ces", ISO6392B: "cze", ISO6393: "ces", Aliases: []string{"česky"}},
+	{Name: "Danish", Autonym: "dansk", ISO6391: "da", ISO6392: "dan", ISO6393: "dan"},
+	{Name: "Dutch", Autonym: "Nederlands", ISO6391: "nl", ISO6392: "nld", ISO6392B: "dut", ISO6393: "nld", Aliases: []string{"Flemish", "Vlaams"}},
+	{Name: "English", Autonym: "English", ISO6391: "en", ISO6392: "eng", ISO6393: "eng"},
+	{Name: "Finnish", Autonym: "suomi", ISO6391: "fi", ISO6392: "fin", ISO6393: "fin"},
+	{Name: "French", Autonym: "français", ISO6391: "fr", ISO6392: "fra", ISO6392B: "fre", ISO6393: "fra"},
+	{Name: "German", Autonym: "Deutsch", ISO6391: "de", ISO6392: "deu", ISO6392B: "ger", ISO6393: "deu"},
+	{Name: "Greek", Autonym: "Ελληνικά", ISO6391: "el", ISO6392: "ell", ISO6392B: "gre", ISO6393: "ell", Aliases: []string{"Modern Greek"}},
+	{Name: "Hebrew", Autonym: "עברית", ISO6391: "he", ISO6392: "heb", ISO6393: "heb", Aliases: []string{"iw"}},
+	{Name: "Hindi", Autonym: "हिन्दी", ISO6391: "hi", ISO6392: "hin", ISO6393: "hin"},
+	{Name: "Hungarian", Autonym: "magyar", ISO6391: "hu", ISO6392: "hun", ISO6393: "hun"},
+	{Name: "Indonesian", Autonym: "Bahasa Indonesia", ISO6391: "id", ISO6392: "ind", ISO6393: "ind", Aliases: []string{"in", "Indonesia"}},
+	{Name: "Italian", Autonym: "italiano", ISO6391: "it", ISO6392: "ita", ISO6393: "ita"},
+	{Name: "Japanese", Autonym: "日本語", ISO6391: "ja", ISO6392: "jpn", ISO6393: "jpn"},
+	{Name: "Korean", Autonym: "한국어", ISO6391: "ko", ISO6392: "kor", ISO6393: "kor", Aliases: []string{"조선말"}},
+	{Name: "Norwegian", Autonym: "norsk", ISO6391: "nb", ISO6392: "nob", ISO6393: "nob", Aliases: []string{"no", "nor", "Bokmål", "Norwegian Bokmål", "norsk bokmål"}},
+	{Name: "Persian", Autonym: "فارسی", ISO6391: "fa", ISO6392: "fas", ISO6392B: "per", ISO6393: "fas", Aliases: []string{"pes", "Farsi"}},
+	{Name: "Polish", Autonym: "polski", ISO6391: "pl", ISO6392: "pol", ISO6393: "pol"},
+	{Name: "Portuguese", Autonym: "português", ISO6391: "pt", ISO6392: "por", ISO6393: "por"},
+	{Name: "Romanian", Autonym: "română", ISO6391: "ro", ISO6392: "ron", ISO6392B: "rum", ISO6393: "ron", Aliases: []string{"mo", "Moldovan"}},
+	{Name: "Russian", Autonym: "русский", ISO6391: "ru", ISO6392: "rus", ISO6393: "rus"},
+	{Name: "Spanish", Autonym: "español", ISO6391: "es", ISO6392: "spa", ISO6393: "spa", Aliases: []string{"Castilian", "castellano"}},
+	{Name: "Swahili", Autonym: "Kiswahili", ISO6391: "sw", ISO6392: "swa", ISO6393: "swa", Aliases: []string{"swh"}},
+	{Name: "Swedish", Autonym: "svenska", ISO6391: "sv", ISO6392: "swe", ISO6393: "swe"},
+	{Name: "Tagalog", Autonym: "Tagalog", ISO6391: "tl", ISO6392: "tgl", ISO6393: "tgl", Aliases: []string{"fil", "Filipino", "Wikang Tagalog"}},
+	{Name: "Thai", Autonym: "ไทย", ISO6391: "th", ISO6392: "tha", ISO6393: "tha", Aliases: []string{"ภาษาไทย"}},
+	{Name: "Turkish", Autonym: "Türkçe", ISO6391: "tr", ISO6392: "tur", ISO6393: "tur"},
+	{Name: "Ukrainian", Autonym: "українська", ISO6391: "uk", ISO6392: "ukr", ISO6393: "ukr"},
+	{Name: "Urdu", Autonym: "اردو", ISO6391: "ur", ISO6392: "urd", ISO6393: "urd"},
+	{Name: "Vietnamese", Autonym: "Tiếng Việt", ISO6391: "vi", ISO6392: "vie", ISO6393: "vie"},
+}
+
+// exonyms are the names of the languages in languages other than English,
+// by ISO 639-1 code and English name. A menu in a language missing here
+// shows autonyms.
+var exonyms = map[string]map[string]string{
+	"de": {
+		"Amharic": "Amharisch", "Arabic": "Arabisch", "Bengali": "Bengalisch",
+		"Catalan": "Katalanisch", "Chinese": "Chinesisch", "Czech": "Tschechisch",
+		"Danish": "Dänisch", "Dutch": "Niederländisch", "English": "Englisch",
+		"Finnish": "Finnisch", "French": "Französisch", "German": "Deutsch",
+		"Greek": "Griechisch", "Hebrew": "Hebräisch", "Hindi": "Hindi",
+		"Hungarian": "Ungarisch", "Indonesian": "Indonesisch", "Italian": "Italienisch",
+		"Japanese": "Japanisch", "Korean": "Koreanisch", "Norwegian": "Norwegisch",
+		"Persian": "Persisch", "Polish": "Polnisch", "Portuguese": "Portugiesisch",
+		"Romanian": "Rumänisch", "Russian": "Russisch", "Spanish": "Spanisch",
+		"Swahili": "Suaheli", "Swedish": "Schwedisch", "Tagalog": "Tagalog",
+		"Thai": "Thailändisch", "Turkish": "Türkisch", "Ukrainian": "Ukrainisch",
+		"Urdu": "Urdu", "Vietnamese": "Vietnamesisch",
+	},
+	"es": {
+		"Amharic": "amárico", "Arabic": "árabe", "Bengali": "bengalí",
+		"Catalan": "catalán", "Chinese": "chino", "Czech": "checo",
+		"Danish": "danés", "Dutch": "neerlandés", "English": "inglés",
+		"Finnish": "finés", "French": "francés", "German": "alemán",
+		"Greek": "griego", "Hebrew": "hebreo", "Hindi": "hindi",
+		"Hungarian": "húngaro", "Indonesian": "indonesio", "Italian": "italiano",
+		"Japanese": "japonés", "Korean": "coreano", "Norwegian": "noruego",
+		"Persian": "persa", "Polish": "polaco", "Portuguese": "portugués",
+		"Romanian": "rumano", "Russian": "ruso", "Spanish": "español",
+		"Swahili": "suajili", "Swedish": "sueco", "Tagalog": "tagalo",
+		"Thai": "tailandés", "Turkish": "turco", "Ukrainian": "ucraniano",
+		"Urdu": "urdu", "Vietnamese": "vietnamita",
+	},
+	"fr": {
+		"Amharic": "amharique", "Arabic": "arabe", "Bengali": "bengali",
+		"Catalan": "catalan", "Chinese": "chinois", "Czech": "tchèque",
+		"Danish": "danois", "Dutch": "néerlandais", "English": "anglais",
+		"Finnish": "finnois", "French": "français", "German": "allemand",
+		"Greek": "grec", "Hebrew": "hébreu", "Hindi": "hindi",
+		"Hungarian": "hongrois", "Indonesian": "indonésien", "Italian": "italien",
+		"Japanese": "japonais", "Korean": "coréen", "Norwegian": "norvégien",
+		"Persian": "persan", "Polish": "polonais", "Portuguese": "portugais",
+		"Romanian": "roumain", "Russian": "russe", "Spanish": "espagnol",
+		"Swahili": "swahili", "Swedish": "suédois", "Tagalog": "tagalog",
+		"Thai": "thaï", "Turkish": "turc", "Ukrainian": "ukrainien",
+		"Urdu": "ourdou", "Vietnamese": "vietnamien",
+	},
+}
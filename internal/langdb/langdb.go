@@ -0,0 +1,164 @@
+// Package langdb knows the languages the greetings are in by every name
+// users type for them: ISO 639 codes, English names, autonyms, names in
+// other languages and common aliases. Lookups ignore case and accents, so
+// "espanol", "ES", "spa" and "Castilian" are all Spanish.
+package langdb
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Language is one language's names and codes.
+type Language struct {
+	Name    string // in English
+	Autonym string // in the language itself
+	ISO6391 string // two letters
+	// ISO6392 is the three-letter terminology code, and ISO6392B the
+	// bibliographic one where it differs, as "ger" does from "deu".
+	ISO6392  string
+	ISO6392B string
+	ISO6393  string
+	Aliases  []string
+}
+
+// Codes returns the language's ISO 639 codes, most specific last.
+func (l Language) Codes() []string {
+	codes := []string{l.ISO6391, l.ISO6392}
+	if l.ISO6392B != "" {
+		codes = append(codes, l.ISO6392B)
+	}
+	if l.ISO6393 != l.ISO6392 {
+		codes = append(codes, l.ISO6393)
+	}
+	return codes
+}
+
+// byName indexes every name of every language, folded.
+var byName = index()
+
+func index() map[string]*Language {
+	m := map[string]*Language{}
+	add := func(name string, l *Language) {
+		k := fold(name)
+		if other, ok := m[k]; ok && other != l {
+			panic(fmt.Sprintf("langdb: %q is both %s and %s", name, other.Name, l.Name))
+		}
+		m[k] = l
+	}
+	for i := range languages {
+		l := &languages[i]
+		add(l.Name, l)
+		add(l.Autonym, l)
+		for _, code := range l.Codes() {
+			add(code, l)
+		}
+		for _, alias := range l.Aliases {
+			add(alias, l)
+		}
+	}
+	for _, names := range exonyms {
+		for english, name := range names {
+			l, ok := m[fold(english)]
+			if !ok {
+				panic("langdb: exonym of unknown language " + english)
+			}
+			add(name, l)
+		}
+	}
+	return m
+}
+
+// Lookup finds the language s names. s may also be a BCP 47 tag such as
+// "pt-BR", which is looked up by its language.
+func Lookup(s string) (Language, bool) {
+	if l, ok := byName[fold(s)]; ok {
+		return *l, true
+	}
+	if i := strings.IndexAny(s, "-_"); i > 0 {
+		if l, ok := byName[fold(s[:i])]; ok {
+			return *l, true
+		}
+	}
+	return Language{}, false
+}
+
+// DisplayName returns the name of lang in inLang, so a menu in French can
+// list "espagnol" and one in Spanish "español". Where the database has no
+// name for lang in inLang, it falls back to lang's autonym, which its
+// speakers will recognize.
+func DisplayName(lang, inLang string) (string, error) {
+	l, ok := Lookup(lang)
+	if !ok {
+		return "", fmt.Errorf("langdb: unknown language %q", lang)
+	}
+	in, ok := Lookup(inLang)
+	if !ok {
+		return "", fmt.Errorf("langdb: unknown language %q", inLang)
+	}
+	switch {
+	case in.Name == "English":
+		return l.Name, nil
+	case in.Name == l.Name:
+		return l.Autonym, nil
+	}
+	if name, ok := exonyms[in.ISO6391][l.Name]; ok {
+		return name, nil
+	}
+	return l.Autonym, nil
+}
+
+// All returns every language, in English alphabetical order.
+func All() []Language {
+	return append([]Language(nil), languages...)
+}
+
+// fold lowercases s, strips its accents and trims and collapses its
+// spaces, so names match however they were typed.
+func fold(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		switch {
+		case r >= 0x300 && r <= 0x36f:
+			// A combining accent, in decomposed text.
+			continue
+		case unicode.IsSpace(r):
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		if base, ok := unaccented[r]; ok {
+			r = base
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// unaccented maps precomposed lowercase letters to their base letter.
+var unaccented = func() map[rune]rune {
+	m := map[rune]rune{}
+	for _, p := range []struct {
+		base    rune
+		accents string
+	}{
+		{'a', "àáâãäåāăąạảấầẩẫậắằẳẵặ"}, {'c', "çćĉċč"}, {'d', "ďđ"},
+		{'e', "èéêëēĕėęěẹẻẽếềểễệ"}, {'g', "ĝğġģ"}, {'h', "ĥ"},
+		{'i', "ìíîïĩīĭįıỉị"}, {'j', "ĵ"}, {'k', "ķ"}, {'l', "ĺļľŀł"},
+		{'n', "ñńņňŉ"}, {'o', "òóôõöōŏőøọỏốồổỗộơớờởỡợ"}, {'r', "ŕŗř"},
+		{'s', "śŝşšș"}, {'t', "ţťț"}, {'u', "ùúûüũūŭůűųụủưứừửữự"},
+		{'w', "ŵ"}, {'y', "ýÿŷỳỵỷỹ"}, {'z', "źżž"},
+		{'α', "ά"}, {'ε', "έ"}, {'η', "ή"}, {'ι', "ίϊΐ"}, {'ο', "ό"},
+		{'υ', "ύϋΰ"}, {'ω', "ώ"},
+	} {
+		for _, r := range p.accents {
+			m[r] = p.base
+		}
+	}
+	return m
+}()
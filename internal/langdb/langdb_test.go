@@ -0,0 +1,100 @@
+package langdb
+
+import "testing"
+
+func TestLookup(t *testing.T) {
+	cases := []struct {
+		input, want string
+	}{
+		{"Spanish", "Spanish"},
+		{"español", "Spanish"},
+		{"ESPANOL", "Spanish"},
+		{"espan\u0303ol", "Spanish"}, // decomposed ñ
+		{"es", "Spanish"},
+		{"spa", "Spanish"},
+		{"Castilian", "Spanish"},
+		{"  castellano ", "Spanish"},
+		{"es-MX", "Spanish"},
+		{"francais", "French"},
+		{"fre", "French"},
+		{"fra", "French"},
+		{"ger", "German"},
+		{"deu", "German"},
+		{"alemán", "German"},
+		{"Allemand", "German"},
+		{"pt_BR", "Portuguese"},
+		{"zh-Hant", "Chinese"},
+		{"Mandarin", "Chinese"},
+		{"中文", "Chinese"},
+		{"Farsi", "Persian"},
+		{"no", "Norwegian"},
+		{"norsk   bokmål", "Norwegian"},
+		{"iw", "Hebrew"},
+		{"ελληνικα", "Greek"},
+		{"tieng viet", "Vietnamese"},
+		{"Filipino", "Tagalog"},
+		{"turkce", "Turkish"},
+		{"cestina", "Czech"},
+	}
+	for _, c := range cases {
+		l, ok := Lookup(c.input)
+		if !ok || l.Name != c.want {
+			t.Errorf("Lookup(%q): got %q, %v want %q", c.input, l.Name, ok, c.want)
+		}
+	}
+
+	for _, input := range []string{"", "Klingon", "xx", "-es"} {
+		if l, ok := Lookup(input); ok {
+			t.Errorf("Lookup(%q): got %s", input, l.Name)
+		}
+	}
+}
+
+func TestDisplayName(t *testing.T) {
+	cases := []struct {
+		lang, inLang, want string
+	}{
+		{"fr", "fr", "français"},
+		{"fr", "en", "French"},
+		{"French", "español", "francés"},
+		{"es", "de", "Spanisch"},
+		{"ja", "fr", "japonais"},
+		{"de", "ja", "Deutsch"}, // no Japanese names: autonyms
+		{"ar", "ru", "العربية"},
+		{"zh", "zh", "中文"},
+	}
+	for _, c := range cases {
+		got, err := DisplayName(c.lang, c.inLang)
+		if got != c.want || err != nil {
+			t.Errorf("DisplayName(%q, %q): got %q, %v want %q", c.lang, c.inLang, got, err, c.want)
+		}
+	}
+
+	if _, err := DisplayName("Klingon", "en"); err == nil {
+		t.Error("want an error for an unknown language")
+	}
+	if _, err := DisplayName("en", "Klingon"); err == nil {
+		t.Error("want an error for an unknown display language")
+	}
+}
+
+func TestData(t *testing.T) {
+	for _, l := range All() {
+		if len(l.ISO6391) != 2 || len(l.ISO6392) != 3 || len(l.ISO6393) != 3 || l.Autonym == "" {
+			t.Errorf("%s: incomplete: %+v", l.Name, l)
+		}
+		if l.ISO6392B == l.ISO6392 {
+			t.Errorf("%s: ISO6392B should only be set where it differs", l.Name)
+		}
+		for in, names := range exonyms {
+			if _, ok := names[l.Name]; !ok {
+				t.Errorf("%s: no name in %s", l.Name, in)
+			}
+		}
+	}
+	for in, names := range exonyms {
+		if len(names) != len(languages) {
+			t.Errorf("%s: %d names for %d languages", in, len(names), len(languages))
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package pronounce
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ipaLetters are the base symbols of the IPA chart (2020 revision), plus
+// ASCII "g", which is written far more often than the IPA's "ɡ".
+const ipaLetters = "" +
+	// Pulmonic consonants.
+	"pbtdʈɖcɟkɡgqɢʔmɱnɳɲŋɴʙrʀⱱɾɽɸβfvθðszʃʒʂʐçʝxɣχʁħʕhɦɬɮʋɹɻjɰlɭʎʟ" +
+	// Non-pulmonic consonants.
+	"ʘǀǃǂǁɓɗʄɠʛ" +
+	// Other consonants.
+	"ʍwɥʜʢʡɕʑɺɧɫ" +
+	// Vowels, including the rhotic ones.
+	"iyɨʉɯuɪʏʊeøɘɵɤoəɛœɜɞʌɔæɐaɶɑɒɚɝ"
+
+// ipaModifiers are the symbols that follow a letter: spacing modifier
+// letters and combining diacritics.
+const ipaModifiers = "" +
+	"ʼʰʷʲˠˤⁿˡ˞ːˑ" +
+	"̥̬̹̜̟̠̩̯̊̈̽" +
+	"̴̤̰̼̝̞̘̙̪̺̻" +
+	"̃̆̚" +
+	// Tone diacritics.
+	"̋́̄̀̏̌̂"
+
+// ipaSeparators stand between letters: stress, syllable and group
+// boundaries, linking, tie bars, tone letters and spaces between words.
+const ipaSeparators = "ˈˌ.|‖‿͜͡˥˦˧˨˩↓↑↗↘ "
+
+// ValidIPA returns an error naming the first symbol in s that isn't IPA.
+// Enclosing slashes or brackets, as in "/e.lɔ.di/", are allowed.
+func ValidIPA(s string) error {
+	s = trimBrackets(s)
+	if s == "" {
+		return fmt.Errorf("pronounce: empty transcription")
+	}
+	prev := ' '
+	for i, r := range s {
+		switch {
+		case strings.ContainsRune(ipaLetters, r):
+		case strings.ContainsRune(ipaModifiers, r):
+			// A modifier needs something to modify.
+			if strings.ContainsRune(ipaSeparators, prev) {
+				return fmt.Errorf("pronounce: %q at %d in %q modifies nothing", r, i, s)
+			}
+		case strings.ContainsRune(ipaSeparators, r):
+		default:
+			return fmt.Errorf("pronounce: %q at %d in %q isn't IPA", r, i, s)
+		}
+		prev = r
+	}
+	return nil
+}
+
+// trimBrackets removes the /phonemic/ or [phonetic] brackets around s.
+func trimBrackets(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '/' && s[len(s)-1] == '/' || s[0] == '[' && s[len(s)-1] == ']') {
+		s = s[1 : len(s)-1]
+	}
+	return s
+}
+
+// xsampa pairs X-SAMPA with IPA. Where two X-SAMPA spellings mean the
+// same symbol, the first is the one IPAToXSAMPA writes.
+var xsampa = [][2]string{
+	{"a", "a"}, {"b", "b"}, {"b_<", "ɓ"}, {"c", "c"}, {"d", "d"}, {"d`", "ɖ"},
+	{"d_<", "ɗ"}, {"e", "e"}, {"f", "f"}, {"g", "ɡ"}, {"g_<", "ɠ"}, {"h", "h"},
+	{`h\`, "ɦ"}, {"i", "i"}, {"j", "j"}, {`j\`, "ʝ"}, {"k", "k"}, {"l", "l"},
+	{"l`", "ɭ"}, {`l\`, "ɺ"}, {"m", "m"}, {"n", "n"}, {"n`", "ɳ"}, {"o", "o"},
+	{"p", "p"}, {`p\`, "ɸ"}, {"q", "q"}, {"r", "r"}, {"r`", "ɽ"}, {`r\`, "ɹ"},
+	{"r\\`", "ɻ"}, {"s", "s"}, {"s`", "ʂ"}, {`s\`, "ɕ"}, {"t", "t"}, {"t`", "ʈ"},
+	{"u", "u"}, {"v", "v"}, {`v\`, "ʋ"}, {"w", "w"}, {"x", "x"}, {`x\`, "ɧ"},
+	{"y", "y"}, {"z", "z"}, {"z`", "ʐ"}, {`z\`, "ʑ"},
+
+	{"A", "ɑ"}, {"B", "β"}, {`B\`, "ʙ"}, {"C", "ç"}, {"D", "ð"}, {"E", "ɛ"},
+	{"F", "ɱ"}, {"G", "ɣ"}, {`G\`, "ɢ"}, {`G\_<`, "ʛ"}, {"H", "ɥ"}, {`H\`, "ʜ"},
+	{"I", "ɪ"}, {"J", "ɲ"}, {`J\`, "ɟ"}, {`J\_<`, "ʄ"}, {"K", "ɬ"}, {`K\`, "ɮ"},
+	{"L", "ʎ"}, {`L\`, "ʟ"}, {"M", "ɯ"}, {`M\`, "ɰ"}, {"N", "ŋ"}, {`N\`, "ɴ"},
+	{"O", "ɔ"}, {`O\`, "ʘ"}, {"P", "ʋ"}, {"Q", "ɒ"}, {"R", "ʁ"}, {`R\`, "ʀ"},
+	{"S", "ʃ"}, {"T", "θ"}, {"U", "ʊ"}, {"V", "ʌ"}, {"W", "ʍ"}, {"X", "χ"},
+	{`X\`, "ħ"}, {"Y", "ʏ"}, {"Z", "ʒ"},
+
+	{"@", "ə"}, {`@\`, "ɘ"}, {"@`", "ɚ"}, {"{", "æ"}, {"}", "ʉ"}, {"1", "ɨ"},
+	{"2", "ø"}, {"3", "ɜ"}, {`3\`, "ɞ"}, {"3`", "ɝ"}, {"4", "ɾ"}, {"5", "ɫ"},
+	{"6", "ɐ"}, {"7", "ɤ"}, {"8", "ɵ"}, {"9", "œ"}, {"&", "ɶ"}, {"?", "ʔ"},
+	{`?\`, "ʕ"}, {`<\`, "ʢ"}, {`>\`, "ʡ"}, {`!\`, "ǃ"}, {`|\`, "ǀ"},
+	{`|\|\`, "ǁ"}, {`=\`, "ǂ"},
+
+	{`"`, "ˈ"}, {"%", "ˌ"}, {":", "ː"}, {`:\`, "ˑ"}, {".", "."}, {"|", "|"},
+	{"||", "‖"}, {`-\`, "‿"}, {" ", " "},
+
+	{"_h", "ʰ"}, {"_w", "ʷ"}, {"'", "ʲ"}, {"_j", "ʲ"}, {"_G", "ˠ"}, {`_?\`, "ˤ"},
+	{"_n", "ⁿ"}, {"_l", "ˡ"}, {"`", "˞"}, {"_>", "ʼ"},
+	{"_0", "̥"}, {"_v", "̬"}, {"~", "̃"}, {"_~", "̃"},
+	{"=", "̩"}, {"_=", "̩"}, {"_^", "̯"}, {"_d", "̪"},
+	{"_a", "̺"}, {"_m", "̻"}, {"_t", "̤"}, {"_k", "̰"},
+	{"_N", "̼"}, {"_r", "̝"}, {"_o", "̞"}, {"_+", "̟"},
+	{"_-", "̠"}, {`_"`, "̈"}, {"_x", "̽"}, {"_A", "̘"},
+	{"_q", "̙"}, {"_}", "̚"}, {"_e", "̴"}, {"_X", "̆"},
+	{"_", "͡"},
+}
+
+var fromXSAMPA, toXSAMPA = xsampaTables()
+
+func xsampaTables() (from, to map[string]string) {
+	from, to = map[string]string{}, map[string]string{}
+	for _, p := range xsampa {
+		from[p[0]] = p[1]
+		if _, ok := to[p[1]]; !ok {
+			to[p[1]] = p[0]
+		}
+	}
+	to["g"] = "g"
+	return from, to
+}
+
+// XSAMPAToIPA converts an X-SAMPA transcription to IPA.
+func XSAMPAToIPA(s string) (string, error) {
+	s = trimBrackets(s)
+	ipa, err := convert(s, fromXSAMPA, 5)
+	if err != nil {
+		return "", fmt.Errorf("pronounce: X-SAMPA %q: %w", s, err)
+	}
+	return ipa, nil
+}
+
+// IPAToXSAMPA converts an IPA transcription to X-SAMPA.
+func IPAToXSAMPA(s string) (string, error) {
+	s = trimBrackets(s)
+	x, err := convert(s, toXSAMPA, 1)
+	if err != nil {
+		return "", fmt.Errorf("pronounce: IPA %q: %w", s, err)
+	}
+	return x, nil
+}
+
+// convert rewrites s with table, always taking the longest match of at
+// most maxLen runes.
+func convert(s string, table map[string]string, maxLen int) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		n, end := 0, i
+		match := -1
+		for n < maxLen && end < len(s) {
+			_, size := utf8.DecodeRuneInString(s[end:])
+			end += size
+			n++
+			if _, ok := table[s[i:end]]; ok {
+				match = end
+			}
+		}
+		if match < 0 {
+			r, _ := utf8.DecodeRuneInString(s[i:])
+			return "", fmt.Errorf("no symbol for %q at %d", r, i)
+		}
+		b.WriteString(table[s[i:match]])
+		i = match
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,151 @@
+// Package pronounce is a dictionary of how names are pronounced in each
+// language, in IPA. Transcriptions can also be given and read back in
+// X-SAMPA, the ASCII spelling of IPA, and the dictionary is kept as CSV.
+package pronounce
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Entry is the pronunciation of a name in a language.
+type Entry struct {
+	Name     string
+	Language string // BCP 47 tag
+	IPA      string // without enclosing slashes
+}
+
+type key struct{ name, language string }
+
+// Dict maps names to their pronunciation. Names are matched without
+// regard to case. It is safe for concurrent use.
+type Dict struct {
+	mu      sync.RWMutex
+	entries map[key]Entry
+}
+
+// New returns an empty Dict.
+func New() *Dict {
+	return &Dict{entries: map[key]Entry{}}
+}
+
+func keyOf(name, language string) key {
+	return key{strings.ToLower(name), strings.ToLower(language)}
+}
+
+// Add sets the pronunciation of name in language, after checking that ipa
+// is IPA.
+func (d *Dict) Add(name, language, ipa string) error {
+	if name == "" || language == "" {
+		return errors.New("pronounce: name and language can't be empty")
+	}
+	if err := ValidIPA(ipa); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries[keyOf(name, language)] = Entry{Name: name, Language: language, IPA: trimBrackets(ipa)}
+	return nil
+}
+
+// AddXSAMPA is Add with the pronunciation in X-SAMPA.
+func (d *Dict) AddXSAMPA(name, language, xsampa string) error {
+	ipa, err := XSAMPAToIPA(xsampa)
+	if err != nil {
+		return err
+	}
+	return d.Add(name, language, ipa)
+}
+
+// Lookup returns the IPA pronunciation of name in language.
+func (d *Dict) Lookup(name, language string) (ipa string, ok bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	e, ok := d.entries[keyOf(name, language)]
+	return e.IPA, ok
+}
+
+// Delete removes the pronunciation of name in language.
+func (d *Dict) Delete(name, language string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.entries, keyOf(name, language))
+}
+
+// Entries returns every entry, sorted by language and then name.
+func (d *Dict) Entries() []Entry {
+	d.mu.RLock()
+	entries := make([]Entry, 0, len(d.entries))
+	for _, e := range d.entries {
+		entries = append(entries, e)
+	}
+	d.mu.RUnlock()
+	slices.SortFunc(entries, func(a, b Entry) int {
+		if c := strings.Compare(a.Language, b.Language); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return entries
+}
+
+// ReadCSV adds the entries of a CSV file with the header
+// "name,language,ipa" or "name,language,xsampa". It stops at the first
+// line it can't use and reports its number.
+func (d *Dict) ReadCSV(r io.Reader) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 3
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("pronounce: reading CSV: %w", err)
+	}
+	add := d.Add
+	switch strings.Join(header, ",") {
+	case "name,language,ipa":
+	case "name,language,xsampa":
+		add = d.AddXSAMPA
+	default:
+		return fmt.Errorf("pronounce: CSV header %q: want name,language,ipa or name,language,xsampa", strings.Join(header, ","))
+	}
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("pronounce: reading CSV: %w", err)
+		}
+		if err := add(rec[0], rec[1], rec[2]); err != nil {
+			line, _ := cr.FieldPos(0)
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+}
+
+// WriteCSV writes every entry as CSV in the format ReadCSV reads, with
+// the transcriptions in X-SAMPA if xsampa is set and in IPA otherwise.
+func (d *Dict) WriteCSV(w io.Writer, xsampa bool) error {
+	cw := csv.NewWriter(w)
+	header := []string{"name", "language", "ipa"}
+	if xsampa {
+		header[2] = "xsampa"
+	}
+	cw.Write(header)
+	for _, e := range d.Entries() {
+		t := e.IPA
+		if xsampa {
+			var err error
+			if t, err = IPAToXSAMPA(t); err != nil {
+				return err
+			}
+		}
+		cw.Write([]string{e.Name, e.Language, t})
+	}
+	cw.Flush()
+	return cw.Error()
+}
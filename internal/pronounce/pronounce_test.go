@@ -0,0 +1,169 @@
+package pronounce
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidIPA(t *testing.T) {
+	valid := []string{
+		"/e.lɔ.di/",
+		"[ˈmæks]",
+		"ʃɪˈvɔːn",
+		"t͡ʃaʊ",
+		"pʰɪn",
+		"ɲ̟u\u0303˥˩",
+		"ɡuːtn̩ ˈtaːk",
+		"gʊd",
+	}
+	for _, s := range valid {
+		if err := ValidIPA(s); err != nil {
+			t.Errorf("ValidIPA(%q): %v", s, err)
+		}
+	}
+
+	invalid := []struct{ s, want string }{
+		{"", "empty transcription"},
+		{"//", "empty transcription"},
+		{"e.lO.di", `'O' at 3 in "e.lO.di" isn't IPA`},
+		{"ʰa", `'ʰ' at 0 in "ʰa" modifies nothing`},
+		{"a.ːb", `modifies nothing`},
+		{"ma1", `'1' at 2 in "ma1" isn't IPA`},
+	}
+	for _, c := range invalid {
+		assertError(t, ValidIPA(c.s), c.want)
+	}
+}
+
+func TestXSAMPA(t *testing.T) {
+	cases := []struct{ xsampa, ipa string }{
+		{"e.lO.di", "e.lɔ.di"},
+		{`S@"vO:n`, "ʃəˈvɔːn"},
+		{"t_SaU", "t͡ʃaʊ"},
+		{"p_hIn", "pʰɪn"},
+		{`gu:tn= "ta:k`, "ɡuːtn̩ ˈtaːk"},
+		{`r\`, "ɹ"},
+		{"r\\`", "ɻ"},
+		{`J\_<`, "ʄ"},
+		{`|\|\`, "ǁ"},
+		{"{", "æ"},
+		{"@`", "ɚ"},
+	}
+	for _, c := range cases {
+		ipa, err := XSAMPAToIPA(c.xsampa)
+		if ipa != c.ipa || err != nil {
+			t.Errorf("XSAMPAToIPA(%q): got %q, %v want %q", c.xsampa, ipa, err, c.ipa)
+		}
+		if err := ValidIPA(ipa); err != nil {
+			t.Errorf("XSAMPAToIPA(%q) isn't valid: %v", c.xsampa, err)
+		}
+		x, err := IPAToXSAMPA(c.ipa)
+		if x != c.xsampa || err != nil {
+			t.Errorf("IPAToXSAMPA(%q): got %q, %v want %q", c.ipa, x, err, c.xsampa)
+		}
+	}
+
+	if x, err := IPAToXSAMPA("gʲ"); x != "g'" || err != nil {
+		t.Errorf("ASCII g: got %q, %v", x, err)
+	}
+	_, err := XSAMPAToIPA("ab$")
+	assertError(t, err, `pronounce: X-SAMPA "ab$": no symbol for '$' at 2`)
+	_, err = IPAToXSAMPA("aé")
+	assertError(t, err, `no symbol for 'é' at 1`)
+}
+
+// TestXSAMPATable checks every pair converts both ways.
+func TestXSAMPATable(t *testing.T) {
+	for _, p := range xsampa {
+		if p[0] == " " {
+			continue // trimmed from a whole transcription
+		}
+		if ipa, err := XSAMPAToIPA(p[0]); ipa != p[1] || err != nil {
+			t.Errorf("XSAMPAToIPA(%q): got %q, %v want %q", p[0], ipa, err, p[1])
+		}
+		x, err := IPAToXSAMPA(p[1])
+		if err != nil {
+			t.Errorf("IPAToXSAMPA(%q): %v", p[1], err)
+		}
+		if back, _ := XSAMPAToIPA(x); back != p[1] {
+			t.Errorf("%q round-trips to %q", p[1], back)
+		}
+	}
+}
+
+func TestDict(t *testing.T) {
+	d := New()
+	if err := d.Add("Elodie", "fr", "/e.lɔ.di/"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.AddXSAMPA("Siobhán", "ga", `S@"vO:n`); err != nil {
+		t.Fatal(err)
+	}
+	assertError(t, d.Add("Max", "en", "maks!"), "isn't IPA")
+	assertError(t, d.Add("", "en", "maks"), "name and language can't be empty")
+
+	if ipa, ok := d.Lookup("elodie", "FR"); ipa != "e.lɔ.di" || !ok {
+		t.Errorf("got %q, %v", ipa, ok)
+	}
+	if _, ok := d.Lookup("Elodie", "en"); ok {
+		t.Error("found Elodie in English")
+	}
+	d.Delete("Siobhán", "ga")
+	if n := len(d.Entries()); n != 1 {
+		t.Errorf("got %d entries after Delete", n)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	const ipaCSV = "name,language,ipa\nSiobhán,ga,ʃəˈvɔːn\nElodie,fr,e.lɔ.di\n\"Jean, Luc\",fr,ʒɑ̃ lyk\n"
+	d := New()
+	if err := d.ReadCSV(strings.NewReader(ipaCSV)); err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := d.WriteCSV(&b, false); err != nil {
+		t.Fatal(err)
+	}
+	want := "name,language,ipa\nElodie,fr,e.lɔ.di\n\"Jean, Luc\",fr,ʒɑ̃ lyk\nSiobhán,ga,ʃəˈvɔːn\n"
+	if b.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	if err := d.WriteCSV(&b, true); err != nil {
+		t.Fatal(err)
+	}
+	want = "name,language,xsampa\nElodie,fr,e.lO.di\n\"Jean, Luc\",fr,ZA~ lyk\nSiobhán,ga,\"S@\"\"vO:n\"\n"
+	if b.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+	}
+
+	// What WriteCSV writes in X-SAMPA reads back the same.
+	again := New()
+	if err := again.ReadCSV(&b); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := again.Entries(), d.Entries(); !slices.Equal(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	bad := []struct{ csv, want string }{
+		{"name,lang,ipa\n", `CSV header "name,lang,ipa"`},
+		{"name,language,ipa\nMax,en,maks\nMax,en,ma$\n", "line 3: pronounce: '$' at 2"},
+		{"name,language,ipa\nMax,en\n", "wrong number of fields"},
+		{"", "EOF"},
+	}
+	for _, c := range bad {
+		assertError(t, New().ReadCSV(strings.NewReader(c.csv)), c.want)
+	}
+}
+
+func assertError(t testing.TB, err error, want string) {
+	t.Helper()
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("got error %v want %q", err, want)
+	}
+}
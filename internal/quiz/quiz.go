@@ -0,0 +1,165 @@
+// Package quiz asks questions about the README's "Common gotchas". Each
+// question is a Go program with either multiple-choice answers or an
+// output to predict, and its answer can be checked by running it.
+package quiz
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"learn-go/internal/sandbox"
+)
+
+// Kind is how a question is answered.
+type Kind string
+
+const (
+	// Choice questions are answered with the number of a choice.
+	Choice Kind = "choice"
+	// Output questions are answered by typing what the program prints.
+	Output Kind = "output"
+)
+
+// Question is one quiz question.
+type Question struct {
+	ID    string `json:"id"`
+	Topic string `json:"topic"`
+	Kind  Kind   `json:"kind"`
+	// Prompt is asked about Code, a complete main package.
+	Prompt  string   `json:"prompt"`
+	Code    string   `json:"code"`
+	Choices []string `json:"choices,omitempty"`
+	// Answer is the correct choice, or the expected output. Only the
+	// first lines of the output are compared, so "panic: ..." matches a
+	// panic without its stack trace.
+	Answer      string `json:"answer"`
+	Explanation string `json:"explanation"`
+}
+
+//go:embed questions/*.json
+var questions embed.FS
+
+// Load returns the built-in questions.
+func Load() ([]Question, error) {
+	return LoadFS(questions, "questions")
+}
+
+// LoadFS reads every *.json file in dir of fsys. Each file holds a list
+// of questions.
+func LoadFS(fsys fs.FS, dir string) ([]Question, error) {
+	names, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var all []Question
+	seen := map[string]bool{}
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		var qs []Question
+		if err := json.Unmarshal(data, &qs); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		for _, q := range qs {
+			if err := q.validate(); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			if seen[q.ID] {
+				return nil, fmt.Errorf("%s: duplicate question %q", name, q.ID)
+			}
+			seen[q.ID] = true
+			all = append(all, q)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+	return all, nil
+}
+
+func (q Question) validate() error {
+	switch {
+	case q.ID == "":
+		return fmt.Errorf("question without an id")
+	case q.Code == "" || q.Answer == "":
+		return fmt.Errorf("question %q needs code and an answer", q.ID)
+	case q.Kind == Choice && !slices.Contains(q.Choices, q.Answer):
+		return fmt.Errorf("question %q: answer %q is not one of its choices", q.ID, q.Answer)
+	case q.Kind != Choice && q.Kind != Output:
+		return fmt.Errorf("question %q: unknown kind %q", q.ID, q.Kind)
+	}
+	return nil
+}
+
+// Correct reports whether answer is right. Choice questions take the
+// 1-based number of a choice.
+func (q Question) Correct(answer string) bool {
+	answer = strings.TrimSpace(answer)
+	if q.Kind == Choice {
+		for i, c := range q.Choices {
+			if answer == fmt.Sprint(i+1) {
+				return c == q.Answer
+			}
+		}
+		return false
+	}
+	return matches(answer, q.Answer)
+}
+
+// matches reports whether output starts with the lines of want, ignoring
+// trailing spaces.
+func matches(output, want string) bool {
+	got := lines(output)
+	expected := lines(want)
+	if len(got) < len(expected) {
+		return false
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func lines(s string) []string {
+	var out []string
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		out = append(out, strings.TrimRight(line, " \t\r"))
+	}
+	return out
+}
+
+// Runner runs a program. *sandbox.Sandbox is one.
+type Runner interface {
+	Run(ctx context.Context, src string) (sandbox.Result, error)
+}
+
+// Verify runs q's code and checks that it really prints q's answer, and
+// that no other choice would also be right.
+func Verify(ctx context.Context, r Runner, q Question) error {
+	res, err := r.Run(ctx, q.Code)
+	if err != nil {
+		return fmt.Errorf("%s: %w", q.ID, err)
+	}
+	if res.BuildErrors != "" {
+		return fmt.Errorf("%s: does not build:\n%s", q.ID, res.BuildErrors)
+	}
+	if !matches(res.Output, q.Answer) {
+		return fmt.Errorf("%s: answer is %q but the program printed %q", q.ID, q.Answer, res.Output)
+	}
+	for _, c := range q.Choices {
+		if c != q.Answer && matches(res.Output, c) {
+			return fmt.Errorf("%s: choice %q is also right", q.ID, c)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package quiz
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"learn-go/internal/sandbox"
+)
+
+var now = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func TestLoad(t *testing.T) {
+	qs, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	topics := map[string]bool{}
+	for _, q := range qs {
+		topics[q.Topic] = true
+	}
+	for _, topic := range []string{"Nil maps", "Loop var capture", "Interface-nil trap", "Slice aliasing"} {
+		if !topics[topic] {
+			t.Errorf("no questions about %s", topic)
+		}
+	}
+
+	t.Run("rejects an answer that isn't a choice", func(t *testing.T) {
+		fsys := fstest.MapFS{"q/bad.json": {Data: []byte(`[{"id": "x", "kind": "choice", "code": "package main", "choices": ["1"], "answer": "2"}]`)}}
+		if _, err := LoadFS(fsys, "q"); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+// TestVerify runs every built-in question to check its answer.
+func TestVerify(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds every question")
+	}
+	qs, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb, err := sandbox.New(sandbox.Config{ModuleRoot: "../.."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range qs {
+		if err := Verify(context.Background(), sb, q); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestCorrect(t *testing.T) {
+	choice := Question{Kind: Choice, Choices: []string{"true", "false"}, Answer: "false"}
+	output := Question{Kind: Output, Answer: "panic: assignment to entry in nil map"}
+
+	cases := []struct {
+		name   string
+		q      Question
+		answer string
+		want   bool
+	}{
+		{"right choice", choice, "2", true},
+		{"wrong choice", choice, "1", false},
+		{"choice out of range", choice, "3", false},
+		{"output", output, "panic: assignment to entry in nil map\n\ngoroutine 1 [running]:", true},
+		{"wrong output", output, "map[]", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.q.Correct(c.answer); got != c.want {
+				t.Errorf("got %v want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestReview(t *testing.T) {
+	var c Card
+	var intervals []time.Duration
+	for i := 0; i < 3; i++ {
+		c = c.Review(true, now)
+		intervals = append(intervals, c.Interval)
+	}
+	want := []time.Duration{day, 6 * day, time.Duration(6 * 2.7 * float64(day)).Round(time.Hour)}
+	for i := range want {
+		if intervals[i] != want[i] {
+			t.Errorf("interval %d: got %v want %v", i, intervals[i], want[i])
+		}
+	}
+
+	c = c.Review(false, now)
+	if c.Streak != 0 || !c.Due.Equal(now) || math.Abs(c.Ease-2.6) > 1e-9 {
+		t.Errorf("after a miss got %+v", c)
+	}
+}
+
+func TestDue(t *testing.T) {
+	qs := []Question{{ID: "new"}, {ID: "later"}, {ID: "overdue"}, {ID: "due"}}
+	d := &Deck{Cards: map[string]Card{
+		"later":   {Due: now.Add(time.Hour)},
+		"overdue": {Due: now.Add(-day)},
+		"due":     {Due: now},
+	}}
+
+	var got []string
+	for _, q := range d.Due(qs, now, 10) {
+		got = append(got, q.ID)
+	}
+	if strings.Join(got, ",") != "overdue,due,new" {
+		t.Errorf("got %q", got)
+	}
+	if len(d.Due(qs, now, 1)) != 1 {
+		t.Errorf("limit not applied")
+	}
+}
+
+func TestSession(t *testing.T) {
+	qs := []Question{
+		{ID: "a", Kind: Choice, Code: "package main", Choices: []string{"0", "1"}, Answer: "1"},
+		{ID: "b", Kind: Output, Code: "package main", Answer: "0 false"},
+	}
+	ask := func(input string) (int, *Deck, string) {
+		t.Helper()
+		var out bytes.Buffer
+		s := &Session{In: strings.NewReader(input), Out: &out, Now: func() time.Time { return now }}
+		deck := &Deck{Cards: map[string]Card{}}
+		right, err := s.Ask(context.Background(), deck, qs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return right, deck, out.String()
+	}
+
+	// b is missed, comes back at the end and is missed again.
+	right, deck, out := ask("2\n0 true\n\n0 true\n\n")
+	if right != 1 || deck.Cards["a"].Correct != 1 || deck.Cards["b"].Wrong != 2 {
+		t.Errorf("got %d right and cards %+v", right, deck.Cards)
+	}
+	if deck.Score() != 33 {
+		t.Errorf("got score %d want 33", deck.Score())
+	}
+	if !strings.Contains(out, "(3/3)") || strings.Count(out, "The answer is:\n    0 false") != 1 {
+		t.Errorf("want b asked again, then its answer, in:\n%s", out)
+	}
+
+	// The second time, b is right: it still counts as missed this
+	// session, but the card is reviewed again.
+	right, deck, _ = ask("2\n0 true\n\n0 false\n\n")
+	if right != 1 || deck.Cards["b"].Wrong != 1 || deck.Cards["b"].Correct != 1 || deck.Cards["b"].Streak != 1 {
+		t.Errorf("got %d right and cards %+v", right, deck.Cards)
+	}
+}
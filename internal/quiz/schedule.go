@@ -0,0 +1,155 @@
+package quiz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DeckFile is where quiz progress is kept, relative to the module root.
+const DeckFile = ".learn/quiz.json"
+
+// Card is the review schedule of one question, following SM-2: every
+// correct answer in a row pushes the next review further out, by a
+// factor that shrinks each time the question is missed.
+type Card struct {
+	Streak   int           `json:"streak"`
+	Ease     float64       `json:"ease"`
+	Interval time.Duration `json:"interval"`
+	Due      time.Time     `json:"due"`
+	Correct  int           `json:"correct"`
+	Wrong    int           `json:"wrong"`
+}
+
+const (
+	day         = 24 * time.Hour
+	initialEase = 2.5
+	minEase     = 1.3
+)
+
+// Review returns c rescheduled after an answer at now.
+func (c Card) Review(correct bool, now time.Time) Card {
+	if c.Ease == 0 {
+		c.Ease = initialEase
+	}
+	if !correct {
+		c.Wrong++
+		c.Streak = 0
+		c.Ease = math.Max(minEase, c.Ease-0.2)
+		// Missed questions come back in the same session.
+		c.Interval = 0
+		c.Due = now
+		return c
+	}
+
+	c.Correct++
+	c.Streak++
+	switch c.Streak {
+	case 1:
+		c.Interval = day
+	case 2:
+		c.Interval = 6 * day
+	default:
+		c.Interval = time.Duration(float64(c.Interval) * c.Ease).Round(time.Hour)
+	}
+	c.Ease += 0.1
+	c.Due = now.Add(c.Interval)
+	return c
+}
+
+// Deck holds the cards of every question seen so far.
+type Deck struct {
+	Cards map[string]Card `json:"cards"`
+}
+
+// LoadDeck reads the deck saved under root. No saved deck is not an
+// error.
+func LoadDeck(root string) (*Deck, error) {
+	d := &Deck{Cards: map[string]Card{}}
+	data, err := os.ReadFile(filepath.Join(root, DeckFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return d, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, d); err != nil {
+		return nil, fmt.Errorf("load quiz progress: %w", err)
+	}
+	if d.Cards == nil {
+		d.Cards = map[string]Card{}
+	}
+	return d, nil
+}
+
+// Save writes d under root.
+func (d *Deck) Save(root string) error {
+	path := filepath.Join(root, DeckFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Answer records an answer to q at now.
+func (d *Deck) Answer(q Question, correct bool, now time.Time) {
+	d.Cards[q.ID] = d.Cards[q.ID].Review(correct, now)
+}
+
+// Due returns up to n questions to ask at now: overdue ones first, most
+// overdue first, then questions never seen.
+func (d *Deck) Due(qs []Question, now time.Time, n int) []Question {
+	var seen, unseen []Question
+	for _, q := range qs {
+		card, ok := d.Cards[q.ID]
+		switch {
+		case !ok:
+			unseen = append(unseen, q)
+		case !card.Due.After(now):
+			seen = append(seen, q)
+		}
+	}
+	sort.SliceStable(seen, func(i, j int) bool {
+		return d.Cards[seen[i].ID].Due.Before(d.Cards[seen[j].ID].Due)
+	})
+
+	due := append(seen, unseen...)
+	if len(due) > n {
+		due = due[:n]
+	}
+	return due
+}
+
+// Score is the share of answers that were right, from 0 to 100.
+func (d *Deck) Score() int {
+	var correct, total int
+	for _, c := range d.Cards {
+		correct += c.Correct
+		total += c.Correct + c.Wrong
+	}
+	if total == 0 {
+		return 0
+	}
+	return correct * 100 / total
+}
+
+// NextDue returns when the earliest card is due, and false if no card
+// has been seen yet.
+func (d *Deck) NextDue() (time.Time, bool) {
+	var next time.Time
+	for _, c := range d.Cards {
+		if next.IsZero() || c.Due.Before(next) {
+			next = c.Due
+		}
+	}
+	return next, !next.IsZero()
+}
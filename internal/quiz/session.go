@@ -0,0 +1,104 @@
+package quiz
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Session asks questions on In and Out.
+type Session struct {
+	In  io.Reader
+	Out io.Writer
+	// Runner, if set, runs each program after it is answered so the
+	// learner sees its real output.
+	Runner Runner
+	Now    func() time.Time
+}
+
+// Ask quizzes on qs, recording answers in deck. A missed question is
+// asked once more at the end. It returns how many of qs were right the
+// first time.
+func (s *Session) Ask(ctx context.Context, deck *Deck, qs []Question) (int, error) {
+	in := bufio.NewScanner(s.In)
+	right := 0
+	queue := slices.Clone(qs)
+	for i := 0; i < len(queue); i++ {
+		q := queue[i]
+		retry := i >= len(qs)
+		fmt.Fprintf(s.Out, "\n(%d/%d) [%s] %s\n\n%s\n", i+1, len(queue), q.Topic, q.Prompt, indent(q.Code))
+
+		var answer string
+		if q.Kind == Choice {
+			for j, c := range q.Choices {
+				fmt.Fprintf(s.Out, "  %d) %s\n", j+1, c)
+			}
+			fmt.Fprint(s.Out, "> ")
+			if !in.Scan() {
+				return right, in.Err()
+			}
+			answer = in.Text()
+		} else {
+			fmt.Fprintln(s.Out, "Type what it prints, then an empty line:")
+			var typed []string
+			for in.Scan() && in.Text() != "" {
+				typed = append(typed, in.Text())
+			}
+			if err := in.Err(); err != nil {
+				return right, err
+			}
+			answer = strings.Join(typed, "\n")
+		}
+
+		correct := q.Correct(answer)
+		deck.Answer(q, correct, s.now())
+		switch {
+		case correct:
+			if !retry {
+				right++
+			}
+			fmt.Fprintln(s.Out, "Correct!")
+		case retry:
+			fmt.Fprintf(s.Out, "Not quite. The answer is:\n%s\n", indent(q.Answer))
+		default:
+			// Keep the answer for after the second try.
+			fmt.Fprintln(s.Out, "Not quite. It comes back at the end.")
+			queue = append(queue, q)
+			continue
+		}
+		if s.Runner != nil {
+			if res, err := s.Runner.Run(ctx, q.Code); err == nil && res.BuildErrors == "" {
+				fmt.Fprintf(s.Out, "Running it prints:\n%s\n", indent(firstLines(res.Output, 3)))
+			}
+		}
+		fmt.Fprintln(s.Out, q.Explanation)
+	}
+	return right, ctx.Err()
+}
+
+func (s *Session) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+func indent(s string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = "    " + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+func firstLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = append(lines[:n], "...")
+	}
+	return strings.Join(lines, "\n")
+}
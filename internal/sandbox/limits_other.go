@@ -0,0 +1,14 @@
+//go:build !unix
+
+package sandbox
+
+import (
+	"context"
+	"os/exec"
+)
+
+// limited returns a command running prog. Only the timeout applies here;
+// rlimits need a unix system.
+func limited(ctx context.Context, cfg Config, prog string) *exec.Cmd {
+	return exec.CommandContext(ctx, prog)
+}
@@ -0,0 +1,25 @@
+//go:build unix
+
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"syscall"
+)
+
+// limited returns a command running prog under cfg's rlimits. The limits
+// are set by a shell that then execs prog, so they apply to prog alone.
+// prog runs in its own process group, which is killed as a whole on
+// timeout.
+func limited(ctx context.Context, cfg Config, prog string) *exec.Cmd {
+	script := fmt.Sprintf(`ulimit -t %d; ulimit -d %d; ulimit -f %d; exec "$0"`,
+		cfg.CPUSeconds, cfg.MemoryBytes>>10, cfg.MaxOutput>>9+1)
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", script, prog)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	return cmd
+}
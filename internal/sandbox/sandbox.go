@@ -0,0 +1,298 @@
+// Package sandbox builds and runs Go snippets with the local toolchain.
+// Each snippet is a main package in a throwaway module that can import
+// this module's packages, such as learn-go/02-integers.
+//
+// The limits are timeouts, rlimits on CPU time, memory and file size, and
+// a cap on output: enough to stop a learner's runaway loop, but not a
+// security boundary. A snippet runs as the server's user and can read and
+// write its files and use the network, so only serve people you would let
+// run code on the host.
+package sandbox
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"learn-go/internal/chapters"
+)
+
+// Config limits what a snippet may use. Zero fields get defaults.
+type Config struct {
+	// ModuleRoot is the module snippets may import from.
+	ModuleRoot string
+	// BuildTimeout bounds compiling a snippet.
+	BuildTimeout time.Duration
+	// RunTimeout is the wall-clock limit for running it.
+	RunTimeout time.Duration
+	// CPUSeconds is the CPU time rlimit for running it.
+	CPUSeconds int
+	// MemoryBytes is the data segment rlimit for running it.
+	MemoryBytes int64
+	// MaxOutput caps the combined stdout and stderr kept.
+	MaxOutput int
+	// MaxConcurrent snippets build and run at once.
+	MaxConcurrent int
+	// CacheEntries is how many results are remembered.
+	CacheEntries int
+}
+
+func (c *Config) setDefaults() {
+	if c.BuildTimeout == 0 {
+		c.BuildTimeout = 60 * time.Second
+	}
+	if c.RunTimeout == 0 {
+		c.RunTimeout = 5 * time.Second
+	}
+	if c.CPUSeconds == 0 {
+		c.CPUSeconds = 5
+	}
+	if c.MemoryBytes == 0 {
+		c.MemoryBytes = 256 << 20
+	}
+	if c.MaxOutput == 0 {
+		c.MaxOutput = 64 << 10
+	}
+	if c.MaxConcurrent == 0 {
+		c.MaxConcurrent = 4
+	}
+	if c.CacheEntries == 0 {
+		c.CacheEntries = 256
+	}
+}
+
+// Result is what running a snippet produced.
+type Result struct {
+	// BuildErrors holds the compiler output if the snippet didn't build.
+	BuildErrors string `json:"build_errors,omitempty"`
+	Output      string `json:"output"`
+	ExitCode    int    `json:"exit_code"`
+	// Truncated is set when Output was cut at MaxOutput.
+	Truncated bool `json:"truncated,omitempty"`
+	TimedOut  bool `json:"timed_out,omitempty"`
+	// Cached is set when the result came from an earlier identical run.
+	Cached bool `json:"cached,omitempty"`
+}
+
+// Sandbox runs snippets. It is safe for concurrent use.
+type Sandbox struct {
+	cfg       Config
+	module    string
+	toolchain string // go env GOVERSION
+	slots     chan struct{}
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]*list.Element
+	lru   *list.List // of cacheEntry, most recent first
+}
+
+type cacheEntry struct {
+	key    [sha256.Size]byte
+	result Result
+}
+
+// New returns a Sandbox for cfg.
+func New(cfg Config) (*Sandbox, error) {
+	cfg.setDefaults()
+	root, err := filepath.Abs(cfg.ModuleRoot)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ModuleRoot = root
+	module, err := chapters.ModulePath(root)
+	if err != nil {
+		return nil, err
+	}
+	goenv := exec.Command("go", "env", "GOVERSION")
+	goenv.Env = append(os.Environ(), "GOTOOLCHAIN=local")
+	version, err := goenv.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go env GOVERSION: %w", err)
+	}
+	return &Sandbox{
+		cfg:       cfg,
+		module:    module,
+		toolchain: strings.TrimSpace(string(version)),
+		slots:     make(chan struct{}, cfg.MaxConcurrent),
+		cache:     map[[sha256.Size]byte]*list.Element{},
+		lru:       list.New(),
+	}, nil
+}
+
+// Run builds and runs src, a complete main package. Results are cached by
+// the snippet, the toolchain and the content of the module it imports
+// from, except for timeouts, which may only mean the host was busy.
+func (s *Sandbox) Run(ctx context.Context, src string) (Result, error) {
+	key, err := s.key(src)
+	if err != nil {
+		return Result{}, err
+	}
+	if r, ok := s.cached(key); ok {
+		return r, nil
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-ctx.Done():
+		return Result{}, ctx.Err()
+	}
+
+	r, err := s.run(ctx, src)
+	if err != nil {
+		return Result{}, err
+	}
+	if !r.TimedOut {
+		s.remember(key, r)
+	}
+	return r, nil
+}
+
+// key hashes src with the toolchain and every file of the module, so
+// editing a chapter a snippet imports, or upgrading Go, runs it afresh.
+// Directories starting with a dot, such as .git, are skipped.
+func (s *Sandbox) key(src string) ([sha256.Size]byte, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%d\x00%s", s.toolchain, len(src), src)
+	err := filepath.WalkDir(s.cfg.ModuleRoot, func(path string, d fs.DirEntry, err error) error {
+		switch {
+		case err != nil:
+			return err
+		case d.IsDir() && path != s.cfg.ModuleRoot && strings.HasPrefix(d.Name(), "."):
+			return filepath.SkipDir
+		case !d.Type().IsRegular():
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(s.cfg.ModuleRoot, path)
+		fmt.Fprintf(h, "\x00%s\x00%d\x00", filepath.ToSlash(rel), len(data))
+		h.Write(data)
+		return nil
+	})
+	var key [sha256.Size]byte
+	if err != nil {
+		return key, fmt.Errorf("hash module: %w", err)
+	}
+	copy(key[:], h.Sum(nil))
+	return key, nil
+}
+
+func (s *Sandbox) run(ctx context.Context, src string) (Result, error) {
+	dir, err := os.MkdirTemp("", "sandbox-")
+	if err != nil {
+		return Result{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	goMod := fmt.Sprintf("module sandbox\n\ngo 1.24\n\nrequire %s v0.0.0\n\nreplace %s => %s\n", s.module, s.module, s.cfg.ModuleRoot)
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o644); err != nil {
+		return Result{}, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(src), 0o644); err != nil {
+		return Result{}, err
+	}
+
+	buildCtx, cancel := context.WithTimeout(ctx, s.cfg.BuildTimeout)
+	defer cancel()
+	build := exec.CommandContext(buildCtx, "go", "build", "-o", "prog", ".")
+	build.Dir = dir
+	build.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOTOOLCHAIN=local", "CGO_ENABLED=0")
+	buildOut := &cappedBuffer{max: s.cfg.MaxOutput}
+	build.Stdout, build.Stderr = buildOut, buildOut
+	if err := build.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return Result{}, err
+		}
+		if ctx.Err() != nil {
+			return Result{}, ctx.Err()
+		}
+		if buildCtx.Err() != nil {
+			return Result{BuildErrors: "build timed out", ExitCode: -1, TimedOut: true}, nil
+		}
+		return Result{BuildErrors: buildOut.String(), ExitCode: exitErr.ExitCode()}, nil
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, s.cfg.RunTimeout)
+	defer cancel()
+	cmd := limited(runCtx, s.cfg, filepath.Join(dir, "prog"))
+	cmd.Dir = dir
+	cmd.Env = []string{"HOME=" + dir, "TMPDIR=" + dir}
+	out := &cappedBuffer{max: s.cfg.MaxOutput}
+	cmd.Stdout, cmd.Stderr = out, out
+	cmd.WaitDelay = time.Second
+
+	err = cmd.Run()
+	r := Result{Output: out.String(), Truncated: out.truncated}
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() != nil:
+		return Result{}, ctx.Err()
+	case runCtx.Err() != nil:
+		r.TimedOut, r.ExitCode = true, -1
+	case errors.As(err, &exitErr):
+		r.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		return Result{}, err
+	}
+	return r, nil
+}
+
+func (s *Sandbox) cached(key [sha256.Size]byte) (Result, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.cache[key]
+	if !ok {
+		return Result{}, false
+	}
+	s.lru.MoveToFront(e)
+	r := e.Value.(cacheEntry).result
+	r.Cached = true
+	return r, true
+}
+
+func (s *Sandbox) remember(key [sha256.Size]byte, r Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.cache[key]; ok {
+		return
+	}
+	s.cache[key] = s.lru.PushFront(cacheEntry{key: key, result: r})
+	for s.lru.Len() > s.cfg.CacheEntries {
+		oldest := s.lru.Back()
+		s.lru.Remove(oldest)
+		delete(s.cache, oldest.Value.(cacheEntry).key)
+	}
+}
+
+// cappedBuffer keeps the first max bytes written to it and quietly
+// drops the rest, so a chatty snippet isn't killed by a write error.
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.buf.Len(); len(p) > room {
+		b.buf.Write(p[:max(room, 0)])
+		b.truncated = true
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *cappedBuffer) String() string { return b.buf.String() }
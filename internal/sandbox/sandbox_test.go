@@ -0,0 +1,186 @@
+package sandbox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newSandbox(t testing.TB, cfg Config) *Sandbox {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("builds snippets with go build")
+	}
+	cfg.ModuleRoot = "../.."
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestRun(t *testing.T) {
+	s := newSandbox(t, Config{RunTimeout: 2 * time.Second, MaxOutput: 1024})
+	ctx := context.Background()
+
+	t.Run("imports the module's chapters", func(t *testing.T) {
+		r, err := s.Run(ctx, `package main
+
+import (
+	"fmt"
+
+	integers "learn-go/02-integers"
+)
+
+func main() { fmt.Println(integers.Add(2, 2)) }
+`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Output != "4\n" || r.ExitCode != 0 || r.BuildErrors != "" {
+			t.Errorf("got %+v", r)
+		}
+	})
+	t.Run("reports build errors", func(t *testing.T) {
+		r, err := s.Run(ctx, "package main\n\nfunc main() { undefined() }\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(r.BuildErrors, "undefined: undefined") || r.ExitCode == 0 {
+			t.Errorf("got %+v", r)
+		}
+	})
+	t.Run("passes the exit code on", func(t *testing.T) {
+		r, err := s.Run(ctx, "package main\n\nimport \"os\"\n\nfunc main() { os.Exit(3) }\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.ExitCode != 3 {
+			t.Errorf("got exit code %d want 3", r.ExitCode)
+		}
+	})
+	t.Run("stops at the timeout", func(t *testing.T) {
+		r, err := s.Run(ctx, "package main\n\nimport \"time\"\n\nfunc main() { time.Sleep(time.Hour) }\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !r.TimedOut {
+			t.Errorf("got %+v, want a timeout", r)
+		}
+		// A timeout may only mean the host was busy, so it isn't cached.
+		again, err := s.Run(ctx, "package main\n\nimport \"time\"\n\nfunc main() { time.Sleep(time.Hour) }\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again.Cached {
+			t.Errorf("got a cached timeout %+v", again)
+		}
+	})
+	t.Run("caps the output", func(t *testing.T) {
+		r, err := s.Run(ctx, "package main\n\nimport \"strings\"\n\nfunc main() { print(strings.Repeat(\"x\", 4096)) }\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r.Output) != 1024 || !r.Truncated {
+			t.Errorf("got %d bytes truncated=%v", len(r.Output), r.Truncated)
+		}
+	})
+	t.Run("caches by content", func(t *testing.T) {
+		src := "package main\n\nfunc main() { println(\"cached\") }\n"
+		first, _ := s.Run(ctx, src)
+		second, err := s.Run(ctx, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first.Cached || !second.Cached || second.Output != first.Output {
+			t.Errorf("got %+v then %+v", first, second)
+		}
+	})
+}
+
+func TestCacheKeyedByModule(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds snippets with go build")
+	}
+	root := t.TempDir()
+	write := func(name, src string) {
+		t.Helper()
+		path := filepath.Join(root, name)
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", "module example.com/greet\n\ngo 1.24\n")
+	write("greet/greet.go", "package greet\n\nconst Hello = \"Hello\"\n")
+	s, err := New(Config{ModuleRoot: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := "package main\n\nimport \"example.com/greet/greet\"\n\nfunc main() { print(greet.Hello) }\n"
+	ctx := context.Background()
+	first, err := s.Run(ctx, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	write("greet/greet.go", "package greet\n\nconst Hello = \"Hola\"\n")
+	second, err := s.Run(ctx, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Output != "Hello" || second.Output != "Hola" || second.Cached {
+		t.Errorf("got %+v then %+v", first, second)
+	}
+}
+
+func TestMemoryLimit(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("rlimits need unix")
+	}
+	s := newSandbox(t, Config{MemoryBytes: 64 << 20})
+
+	r, err := s.Run(context.Background(), `package main
+
+func main() {
+	b := make([]byte, 512<<20)
+	for i := range b {
+		b[i] = 1
+	}
+	println(len(b))
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ExitCode == 0 {
+		t.Errorf("allocating past the limit succeeded: %+v", r)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	s := newSandbox(t, Config{})
+	srv := httptest.NewServer(Handler(s))
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/run", "text/plain", strings.NewReader("package main\n\nfunc main() { println(\"hi\") }\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var r Result
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Output != "hi\n" {
+		t.Errorf("got %+v", r)
+	}
+}
@@ -0,0 +1,229 @@
+// Package scaffold creates new chapters in the shape of the existing ones:
+// a package with a stub, a table-driven test, an Example and a README
+// section.
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"learn-go/internal/chapters"
+)
+
+// ErrExists is returned instead of overwriting an existing chapter.
+var ErrExists = errors.New("chapter already exists")
+
+var chapterArg = regexp.MustCompile(`^(?:(\d+)-)?([a-z][a-z0-9]*(?:-[a-z0-9]+)*)$`)
+
+// New creates the next chapter under root. name is either a bare topic
+// like "iteration" or a numbered one like "03-iteration"; a number must
+// be the next free one.
+func New(root, name string) (chapters.Chapter, error) {
+	m := chapterArg.FindStringSubmatch(name)
+	if m == nil {
+		return chapters.Chapter{}, fmt.Errorf("bad chapter name %q: want a lowercase topic like \"iteration\" or \"03-iteration\"", name)
+	}
+	topic := m[2]
+
+	existing, err := chapters.Discover(root)
+	if err != nil {
+		return chapters.Chapter{}, err
+	}
+	next := 1
+	for _, ch := range existing {
+		if strings.SplitN(ch.Name, "-", 2)[1] == topic {
+			return chapters.Chapter{}, fmt.Errorf("%s: %w", ch.Name, ErrExists)
+		}
+		next = ch.Number + 1
+	}
+	if m[1] != "" {
+		if n, _ := strconv.Atoi(m[1]); n != next {
+			return chapters.Chapter{}, fmt.Errorf("chapter %s: the next chapter number is %02d", name, next)
+		}
+	}
+
+	ch := chapters.Chapter{Number: next, Name: fmt.Sprintf("%02d-%s", next, topic)}
+	ch.Dir = filepath.Join(root, ch.Name)
+
+	module, err := chapters.ModulePath(root)
+	if err != nil {
+		return chapters.Chapter{}, err
+	}
+	data := chapterData{
+		Module:  module,
+		Chapter: ch.Name,
+		Package: strings.ReplaceAll(topic, "-", ""),
+		Func:    exportedName(topic),
+	}
+	readme := filepath.Join(root, "README.md")
+	section, err := render(readmeTemplate, data)
+	if err != nil {
+		return chapters.Chapter{}, err
+	}
+	if readmeHas(readme, "### "+ch.Name) {
+		return chapters.Chapter{}, fmt.Errorf("README section %s: %w", ch.Name, ErrExists)
+	}
+
+	if err := os.Mkdir(ch.Dir, 0o755); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			err = fmt.Errorf("%s: %w", ch.Name, ErrExists)
+		}
+		return chapters.Chapter{}, err
+	}
+	file := strings.ReplaceAll(topic, "-", "_")
+	files := []struct {
+		name string
+		tmpl *template.Template
+	}{
+		{file + ".go", sourceTemplate},
+		{file + "_test.go", testTemplate},
+	}
+	for _, f := range files {
+		src, err := render(f.tmpl, data)
+		if err == nil {
+			src, err = format.Source(src)
+		}
+		if err == nil {
+			err = writeNew(filepath.Join(ch.Dir, f.name), src)
+		}
+		if err != nil {
+			os.RemoveAll(ch.Dir)
+			return chapters.Chapter{}, err
+		}
+	}
+
+	if err := appendSection(readme, section); err != nil {
+		os.RemoveAll(ch.Dir)
+		return chapters.Chapter{}, err
+	}
+	return ch, nil
+}
+
+type chapterData struct {
+	Module  string // module path, e.g. "learn-go"
+	Chapter string // directory, e.g. "03-iteration"
+	Package string // package name, e.g. "iteration"
+	Func    string // stub function, e.g. "Iteration"
+}
+
+func render(t *template.Template, data chapterData) ([]byte, error) {
+	var buf bytes.Buffer
+	err := t.Execute(&buf, data)
+	return buf.Bytes(), err
+}
+
+func writeNew(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// exportedName turns "functional-options" into "FunctionalOptions".
+func exportedName(topic string) string {
+	var b strings.Builder
+	for _, word := range strings.Split(topic, "-") {
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+func readmeHas(path, heading string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == heading {
+			return true
+		}
+	}
+	return false
+}
+
+// appendSection adds section to the end of the README's "## Chapters"
+// section, creating it if needed.
+func appendSection(path string, section []byte) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	readme := string(data)
+
+	start := strings.Index(readme, "\n## Chapters\n")
+	if start < 0 {
+		readme = strings.TrimRight(readme, "\n") + "\n\n## Chapters\n"
+		start = strings.Index(readme, "\n## Chapters\n")
+	}
+	end := len(readme)
+	if next := strings.Index(readme[start+1:], "\n## "); next >= 0 {
+		end = start + 1 + next
+	}
+
+	before := strings.TrimRight(readme[:end], "\n") + "\n\n"
+	after := readme[end:]
+	if after != "" {
+		after = "\n" + strings.TrimLeft(after, "\n")
+	}
+	return os.WriteFile(path, []byte(before+string(section)+after), 0o644)
+}
+
+var sourceTemplate = template.Must(template.New("source").Parse(`package {{.Package}}
+
+// {{.Func}} is where this chapter starts. Write a failing test first,
+// then change it until the test passes.
+func {{.Func}}(input string) string {
+	return input
+}
+`))
+
+var testTemplate = template.Must(template.New("test").Parse(`package {{.Package}}
+
+import (
+	"fmt"
+	"testing"
+
+	"{{.Module}}/internal/assert"
+)
+
+func Test{{.Func}}(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"returns its input", "gopher", "gopher"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, {{.Func}}(c.input), c.want)
+		})
+	}
+}
+
+func Example{{.Func}}() {
+	fmt.Println({{.Func}}("gopher"))
+	// Output: gopher
+}
+`))
+
+var readmeTemplate = template.Must(template.New("readme").Parse(`### {{.Chapter}}
+
+TODO: what this chapter teaches.
+
+- Code: [` + "`{{.Chapter}}`" + `]({{.Chapter}})
+- Run: ` + "`go test ./{{.Chapter}}`" + `
+`))
@@ -0,0 +1,94 @@
+package scaffold
+
+import (
+	"errors"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const readme = `# Learn Go
+
+## Chapters
+
+### 01-hello-world
+
+- Code: [` + "`01-hello-world`" + `](01-hello-world)
+
+## Trivia
+
+- Go has no ternary operator
+`
+
+func TestNew(t *testing.T) {
+	t.Run("creates the next chapter", func(t *testing.T) {
+		root := newRoot(t)
+
+		ch, err := New(root, "iteration")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ch.Name != "02-iteration" {
+			t.Errorf("got chapter %q want %q", ch.Name, "02-iteration")
+		}
+
+		for _, name := range []string{"iteration.go", "iteration_test.go"} {
+			src, err := os.ReadFile(filepath.Join(ch.Dir, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			formatted, err := format.Source(src)
+			if err != nil || string(formatted) != string(src) {
+				t.Errorf("%s is not gofmt'd Go: %v", name, err)
+			}
+		}
+		test, _ := os.ReadFile(filepath.Join(ch.Dir, "iteration_test.go"))
+		for _, want := range []string{`"example.com/learn/internal/assert"`, "func TestIteration(", "func ExampleIteration()", "// Output: gopher"} {
+			if !strings.Contains(string(test), want) {
+				t.Errorf("test file is missing %s", want)
+			}
+		}
+
+		got, _ := os.ReadFile(filepath.Join(root, "README.md"))
+		section := strings.Index(string(got), "### 02-iteration")
+		if section < 0 || section > strings.Index(string(got), "## Trivia") {
+			t.Errorf("README section missing or outside Chapters:\n%s", got)
+		}
+	})
+	t.Run("accepts the next number", func(t *testing.T) {
+		root := newRoot(t)
+		if _, err := New(root, "02-functional-options"); err != nil {
+			t.Fatal(err)
+		}
+		src, _ := os.ReadFile(filepath.Join(root, "02-functional-options", "functional_options.go"))
+		if !strings.Contains(string(src), "package functionaloptions") || !strings.Contains(string(src), "func FunctionalOptions(") {
+			t.Errorf("unexpected source:\n%s", src)
+		}
+	})
+	t.Run("rejects another number", func(t *testing.T) {
+		root := newRoot(t)
+		if _, err := New(root, "05-iteration"); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("refuses to overwrite", func(t *testing.T) {
+		root := newRoot(t)
+		for _, name := range []string{"hello-world", "02-integers"} {
+			os.MkdirAll(filepath.Join(root, "02-integers"), 0o755)
+			if _, err := New(root, name); !errors.Is(err, ErrExists) {
+				t.Errorf("%s: got %v want ErrExists", name, err)
+			}
+		}
+	})
+}
+
+func newRoot(t testing.TB) string {
+	t.Helper()
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/learn\n\ngo 1.24\n"), 0o644)
+	os.WriteFile(filepath.Join(root, "README.md"), []byte(readme), 0o644)
+	os.Mkdir(filepath.Join(root, "01-hello-world"), 0o755)
+	return root
+}
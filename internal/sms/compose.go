@@ -0,0 +1,52 @@
+package sms
+
+// precomposed is, for each combining accent, the letters it composes with
+// and what they compose to, in Latin-1 and Latin Extended-A, and
+// Romanian's comma-below letters. Text typed
+// on some keyboards or copied from macOS file names has "E" and U+0301
+// where others have "É", and only the second is in GSM-7.
+var precomposed = map[rune][2]string{
+	0x0300: {"AEIOUaeiou", "ÀÈÌÒÙàèìòù"},                             // grave
+	0x0301: {"AEIOUYaeiouyCcLlNnRrSsZz", "ÁÉÍÓÚÝáéíóúýĆćĹĺŃńŔŕŚśŹź"}, // acute
+	0x0302: {"AEIOUaeiouCcGgHhJjSsWwYy", "ÂÊÎÔÛâêîôûĈĉĜĝĤĥĴĵŜŝŴŵŶŷ"}, // circumflex
+	0x0303: {"ANOanoIiUu", "ÃÑÕãñõĨĩŨũ"},                             // tilde
+	0x0304: {"AaEeIiOoUu", "ĀāĒēĪīŌōŪū"},                             // macron
+	0x0306: {"AaEeGgIiOoUu", "ĂăĔĕĞğĬĭŎŏŬŭ"},                         // breve
+	0x0307: {"CcEeGgIZz", "ĊċĖėĠġİŻż"},                               // dot above
+	0x0308: {"AEIOUaeiouyY", "ÄËÏÖÜäëïöüÿŸ"},                         // diaeresis
+	0x030a: {"AaUu", "ÅåŮů"},                                         // ring above
+	0x030b: {"OoUu", "ŐőŰű"},                                         // double acute
+	0x030c: {"CcDdEeLlNnRrSsTtZz", "ČčĎďĚěĽľŇňŘřŠšŤťŽž"},             // caron
+	0x0326: {"SsTt", "ȘșȚț"},                                         // comma below
+	0x0327: {"CcGgKkLlNnRrSsTt", "ÇçĢģĶķĻļŅņŖŗŞşŢţ"},                 // cedilla
+	0x0328: {"AaEeIiUu", "ĄąĘęĮįŲų"},                                 // ogonek
+}
+
+// compositions indexes precomposed by letter and accent.
+var compositions = func() map[[2]rune]rune {
+	m := map[[2]rune]rune{}
+	for accent, p := range precomposed {
+		composed := []rune(p[1])
+		for i, base := range []rune(p[0]) {
+			m[[2]rune{base, accent}] = composed[i]
+		}
+	}
+	return m
+}()
+
+// compose replaces a letter followed by a combining accent with the
+// precomposed letter where there is one, as Unicode normalization form
+// NFC would for these letters. Accents it can't compose are kept.
+func compose(text string) string {
+	var out []rune
+	for _, r := range text {
+		if n := len(out); n > 0 {
+			if c, ok := compositions[[2]rune{out[n-1], r}]; ok {
+				out[n-1] = c
+				continue
+			}
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
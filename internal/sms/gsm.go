@@ -0,0 +1,54 @@
+package sms
+
+// gsmBasic is the GSM 03.38 default alphabet, in code order. Code 0x1b,
+// here a placeholder, is the escape to the extension table.
+const gsmBasic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞ\x1bÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+const gsmEscape = 0x1b
+
+// gsmExtension is the GSM 03.38 extension table: characters sent as the
+// escape code followed by their own, so each takes two septets.
+var gsmExtension = map[rune]byte{
+	'\f': 0x0a, '^': 0x14, '{': 0x28, '}': 0x29, '\\': 0x2f,
+	'[': 0x3c, '~': 0x3d, ']': 0x3e, '|': 0x40, '€': 0x65,
+}
+
+var gsmCodes = func() map[rune]byte {
+	codes := map[rune]byte{}
+	code := byte(0)
+	for _, r := range gsmBasic {
+		if code != gsmEscape {
+			codes[r] = code
+		}
+		code++
+	}
+	return codes
+}()
+
+// septets returns r in GSM-7: one septet, two for the extension table, or
+// none if GSM-7 lacks r.
+func septets(r rune) []byte {
+	if c, ok := gsmCodes[r]; ok {
+		return []byte{c}
+	}
+	if c, ok := gsmExtension[r]; ok {
+		return []byte{gsmEscape, c}
+	}
+	return nil
+}
+
+// pack packs septets into octets, least significant bit first, after
+// offset bits left for a user data header and the fill bits that align
+// the text to a septet boundary.
+func pack(septets []byte, offset int) []byte {
+	out := make([]byte, (offset+7*len(septets)+7)/8)
+	for i, s := range septets {
+		bit := offset + 7*i
+		out[bit/8] |= s << (bit % 8)
+		if bit%8 > 1 {
+			out[bit/8+1] |= s >> (8 - bit%8)
+		}
+	}
+	return out
+}
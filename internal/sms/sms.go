@@ -0,0 +1,200 @@
+// Package sms encodes text for SMS. A message is sent in GSM-7, 160
+// characters of a 7-bit alphabet for Western European languages, when
+// every character is in it; otherwise in UCS-2, 70 UTF-16 code units.
+// Longer messages are sent as concatenated parts, each starting with a
+// user data header that lets the phone join them, which leaves 153
+// septets or 67 code units for text.
+package sms
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync/atomic"
+	"unicode/utf16"
+)
+
+// Encoding is how a message's characters are encoded.
+type Encoding int
+
+const (
+	GSM7 Encoding = iota
+	UCS2
+)
+
+func (e Encoding) String() string {
+	if e == UCS2 {
+		return "UCS-2"
+	}
+	return "GSM-7"
+}
+
+// The room for text in one message, and in each part of a concatenated
+// one: 140 octets, less the 6 octets of header.
+const (
+	gsmSingle  = 160 // septets
+	gsmPart    = 153
+	ucs2Single = 70 // UTF-16 code units
+	ucs2Part   = 67
+
+	// maxParts is as many parts as the header can count.
+	maxParts = 255
+)
+
+// Options change how a message is encoded.
+type Options struct {
+	// Transliterate drops accents and replaces typographic punctuation
+	// GSM-7 lacks when that lets the whole message be sent in GSM-7,
+	// the shorter encoding: "Olá, Zoë" is sent as "Ola, Zoe".
+	Transliterate bool
+
+	// Reference identifies the parts of one concatenated message. If it
+	// is 0, Encode picks one, different from the last one it picked, so
+	// that phones don't join the parts of two messages.
+	Reference byte
+}
+
+// lastReference is the last Reference Encode picked. It starts at random
+// so that a restarted sender doesn't reuse the references it just sent.
+var lastReference atomic.Uint32
+
+func init() {
+	lastReference.Store(rand.Uint32())
+}
+
+// newReference returns the next reference, skipping 0.
+func newReference() byte {
+	for {
+		if ref := byte(lastReference.Add(1)); ref != 0 {
+			return ref
+		}
+	}
+}
+
+// Message is text encoded for SMS.
+type Message struct {
+	Text     string // as sent, after any transliteration
+	Encoding Encoding
+	Length   int // in septets or UTF-16 code units
+	Parts    []Part
+}
+
+// Part is one SMS of a message.
+type Part struct {
+	Text string
+
+	// Header is the user data header of a part of a concatenated message:
+	// 05 00 03, then the reference, the number of parts and this part's
+	// number, from 1. It is nil if the message is sent as one SMS.
+	Header []byte
+
+	// UserData is the TP-User-Data field: Header, then the text, as
+	// packed septets or big-endian UTF-16.
+	UserData []byte
+}
+
+// Encode encodes text for SMS. Letters followed by combining accents are
+// composed first, so "E\u0301" is sent as GSM-7's "É". It returns an
+// error for text too long for 255 parts.
+func Encode(text string, o Options) (Message, error) {
+	text = compose(text)
+	if o.Transliterate && !isGSM7(text) {
+		if t, ok := transliterate(text); ok {
+			text = t
+		}
+	}
+	if isGSM7(text) {
+		return split(text, GSM7, gsmSingle, gsmPart, o.Reference)
+	}
+	return split(text, UCS2, ucs2Single, ucs2Part, o.Reference)
+}
+
+func isGSM7(text string) bool {
+	for _, r := range text {
+		if septets(r) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// encode returns r as septets in GSM-7, or as big-endian UTF-16 in UCS-2.
+func (e Encoding) encode(r rune) []byte {
+	if e == GSM7 {
+		return septets(r)
+	}
+	var b []byte
+	for _, u := range utf16.Encode([]rune{r}) {
+		b = append(b, byte(u>>8), byte(u))
+	}
+	return b
+}
+
+// unit is the size of a septet or a code unit in what encode returns.
+func (e Encoding) unit() int {
+	if e == UCS2 {
+		return 2
+	}
+	return 1
+}
+
+// split sends text as one SMS if it fits single septets or code units,
+// and otherwise splits it into parts of at most part. A character is
+// never split between parts, neither an extension table character nor a
+// surrogate pair.
+func split(text string, e Encoding, single, part int, ref byte) (Message, error) {
+	m := Message{Text: text, Encoding: e}
+	var all []byte
+	for _, r := range text {
+		all = append(all, e.encode(r)...)
+	}
+	m.Length = len(all) / e.unit()
+	if m.Length <= single {
+		m.Parts = []Part{{Text: text, UserData: userData(e, nil, all)}}
+		return m, nil
+	}
+
+	var (
+		texts []string
+		data  [][]byte
+		start int
+		cur   []byte
+	)
+	for i, r := range text {
+		b := e.encode(r)
+		if (len(cur)+len(b))/e.unit() > part {
+			texts, data = append(texts, text[start:i]), append(data, cur)
+			start, cur = i, nil
+		}
+		cur = append(cur, b...)
+	}
+	texts, data = append(texts, text[start:]), append(data, cur)
+
+	if len(texts) > maxParts {
+		return Message{}, fmt.Errorf("sms: text needs %d parts, more than %d", len(texts), maxParts)
+	}
+	if ref == 0 {
+		ref = newReference()
+	}
+	for i := range texts {
+		header := []byte{5, 0, 3, ref, byte(len(texts)), byte(i + 1)}
+		m.Parts = append(m.Parts, Part{
+			Text:     texts[i],
+			Header:   header,
+			UserData: userData(e, header, data[i]),
+		})
+	}
+	return m, nil
+}
+
+// userData puts header before the encoded text. In GSM-7, the septets are
+// packed, starting at the first septet boundary after the header.
+func userData(e Encoding, header, text []byte) []byte {
+	if e == UCS2 {
+		return append(append([]byte{}, header...), text...)
+	}
+	offset := 8 * len(header)
+	offset += (7 - offset%7) % 7
+	ud := pack(text, offset)
+	copy(ud, header)
+	return ud
+}
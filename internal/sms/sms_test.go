@@ -0,0 +1,208 @@
+package sms
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestGSMAlphabet(t *testing.T) {
+	if n := utf8.RuneCountInString(gsmBasic); n != 128 {
+		t.Fatalf("the basic table has %d characters, want 128", n)
+	}
+	if len(gsmCodes) != 127 {
+		t.Errorf("got %d codes, want 127", len(gsmCodes))
+	}
+	for r, code := range gsmExtension {
+		if _, ok := gsmCodes[r]; ok {
+			t.Errorf("%q is in both tables", r)
+		}
+		if code >= 0x80 {
+			t.Errorf("%q has code %#x", r, code)
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	euro152 := strings.Repeat("a", 152) + "€" + strings.Repeat("a", 9)
+	emoji66 := strings.Repeat("é", 66) + "😀" + strings.Repeat("é", 3)
+	cases := []struct {
+		name     string
+		text     string
+		encoding Encoding
+		length   int
+		parts    []int // characters in each part
+	}{
+		{"empty", "", GSM7, 0, []int{0}},
+		{"greeting", "¡Hola, Mundo!", GSM7, 13, []int{13}},
+		{"160 septets", strings.Repeat("a", 160), GSM7, 160, []int{160}},
+		{"161 septets", strings.Repeat("a", 161), GSM7, 161, []int{153, 8}},
+		{"306 septets", strings.Repeat("a", 306), GSM7, 306, []int{153, 153}},
+		{"307 septets", strings.Repeat("a", 307), GSM7, 307, []int{153, 153, 1}},
+		{"80 euros", strings.Repeat("€", 80), GSM7, 160, []int{80}},
+		{"81 euros", strings.Repeat("€", 81), GSM7, 162, []int{76, 5}},
+		{"escape not split", euro152, GSM7, 163, []int{152, 10}},
+		{"70 code units", strings.Repeat("ő", 70), UCS2, 70, []int{70}},
+		{"71 code units", strings.Repeat("ő", 71), UCS2, 71, []int{67, 4}},
+		{"134 code units", strings.Repeat("你", 134), UCS2, 134, []int{67, 67}},
+		{"135 code units", strings.Repeat("你", 135), UCS2, 135, []int{67, 67, 1}},
+		{"surrogate pair not split", emoji66, UCS2, 71, []int{66, 4}},
+		{"one character forces UCS-2", "Olá, Max", UCS2, 8, []int{8}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := encode(t, c.text, Options{})
+			if m.Encoding != c.encoding || m.Length != c.length || m.Text != c.text {
+				t.Errorf("got %v of length %d, want %v of length %d", m.Encoding, m.Length, c.encoding, c.length)
+			}
+			if len(m.Parts) != len(c.parts) {
+				t.Fatalf("got %d parts, want %d", len(m.Parts), len(c.parts))
+			}
+			var joined strings.Builder
+			for i, p := range m.Parts {
+				if n := utf8.RuneCountInString(p.Text); n != c.parts[i] {
+					t.Errorf("part %d has %d characters, want %d", i+1, n, c.parts[i])
+				}
+				if len(p.UserData) > 140 {
+					t.Errorf("part %d has %d octets of user data", i+1, len(p.UserData))
+				}
+				joined.WriteString(p.Text)
+			}
+			if joined.String() != c.text {
+				t.Errorf("parts join to %q", joined.String())
+			}
+		})
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	m := encode(t, strings.Repeat("a", 161), Options{Reference: 0x2a})
+	for i, want := range []string{"0500032a0201", "0500032a0202"} {
+		p := m.Parts[i]
+		if got := hex.EncodeToString(p.Header); got != want {
+			t.Errorf("part %d: got header %s want %s", i+1, got, want)
+		}
+		if !bytes.HasPrefix(p.UserData, p.Header) {
+			t.Errorf("part %d: user data doesn't start with the header", i+1)
+		}
+	}
+	if got := len(m.Parts[0].UserData); got != 140 {
+		t.Errorf("a full part has %d octets, want 140", got)
+	}
+	if m := encode(t, "Hello", Options{}); m.Parts[0].Header != nil {
+		t.Errorf("a single SMS has header %x", m.Parts[0].Header)
+	}
+}
+
+func TestReference(t *testing.T) {
+	long := strings.Repeat("a", 161)
+	first, second := encode(t, long, Options{}), encode(t, long, Options{})
+	a, b := first.Parts[0].Header[3], second.Parts[0].Header[3]
+	if a == 0 || b == 0 || a == b {
+		t.Errorf("two messages without a reference got %#x and %#x", a, b)
+	}
+	for _, p := range first.Parts {
+		if p.Header[3] != a {
+			t.Errorf("the parts of one message have references %#x and %#x", a, p.Header[3])
+		}
+	}
+}
+
+func TestTooManyParts(t *testing.T) {
+	if m := encode(t, strings.Repeat("a", 255*gsmPart), Options{}); len(m.Parts) != 255 {
+		t.Errorf("got %d parts, want 255", len(m.Parts))
+	}
+	if _, err := Encode(strings.Repeat("a", 255*gsmPart+1), Options{}); err == nil {
+		t.Error("want an error for 256 parts")
+	}
+}
+
+func TestCompose(t *testing.T) {
+	cases := []struct {
+		text, want string
+	}{
+		{"E\u0301lodie", "Élodie"},
+		{"Franc\u0327ois", "François"},
+		{"S\u0326tefan", "Ștefan"},
+		{"A\u030asa", "Åsa"},
+		{"\u0301", "\u0301"},
+		{"x\u0301", "x\u0301"},       // no precomposed x with an acute
+		{"e\u0301\u0301", "é\u0301"}, // one accent per letter
+	}
+	for _, c := range cases {
+		if got := compose(c.text); got != c.want {
+			t.Errorf("compose(%q) = %q, want %q", c.text, got, c.want)
+		}
+	}
+}
+
+func TestUserData(t *testing.T) {
+	cases := []struct {
+		text string
+		want string
+	}{
+		// From GSM 03.38 and the SMS PDU examples that use it.
+		{"hellohello", "e8329bfd4697d9ec37"},
+		{"Hi", "c834"},
+		{"€", "9b32"},
+		{"Olá", "004f006c00e1"},
+		{"😀", "d83dde00"},
+	}
+	for _, c := range cases {
+		got := hex.EncodeToString(encode(t, c.text, Options{}).Parts[0].UserData)
+		if got != c.want {
+			t.Errorf("%q: got %s want %s", c.text, got, c.want)
+		}
+	}
+
+	// After the 6-octet header, one fill bit aligns the first septet.
+	m := encode(t, strings.Repeat("a", 161), Options{})
+	if got := m.Parts[1].UserData[6:]; got[0] != 0x61<<1 {
+		t.Errorf("got %x, want the first septet after one fill bit", got)
+	}
+}
+
+func TestTransliterate(t *testing.T) {
+	cases := []struct {
+		text, want string
+		encoding   Encoding
+	}{
+		{"Olá, Zoë", "Ola, Zoe", GSM7},
+		{"Bonjour, François\u202f!", "Bonjour, Francois !", GSM7},
+		{"Cześć, Łukasz", "Czesc, Lukasz", GSM7},
+		{"Szia, Győző", "Szia, Gyözö", GSM7},
+		// A decomposed É, "E" and U+0301, is composed to GSM-7's.
+		{"Bonjour, E\u0301lodie", "Bonjour, Élodie", GSM7},
+		{"Olá, Zoe\u0308", "Ola, Zoe", GSM7},
+		{"“Hi” – it’s…", "\"Hi\" - it's...", GSM7},
+		{"Hej, Åsa", "Hej, Åsa", GSM7},
+		{"你好，Zoë", "你好，Zoë", UCS2},
+		{"Olá 👋", "Olá 👋", UCS2},
+	}
+	for _, c := range cases {
+		m := encode(t, c.text, Options{Transliterate: true})
+		if m.Text != c.want || m.Encoding != c.encoding {
+			t.Errorf("%q: got %q in %v, want %q in %v", c.text, m.Text, m.Encoding, c.want, c.encoding)
+		}
+	}
+
+	// 100 accented characters take two parts in UCS-2, one in GSM-7.
+	text := strings.Repeat("ú", 100)
+	if n := len(encode(t, text, Options{}).Parts); n != 2 {
+		t.Errorf("got %d parts without transliteration, want 2", n)
+	}
+	if n := len(encode(t, text, Options{Transliterate: true}).Parts); n != 1 {
+		t.Errorf("got %d parts with transliteration, want 1", n)
+	}
+}
+
+func encode(t testing.TB, text string, o Options) Message {
+	t.Helper()
+	m, err := Encode(text, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
@@ -0,0 +1,47 @@
+package sms
+
+import "strings"
+
+// transliterations spell characters GSM-7 lacks with ones it has: Latin
+// letters without their accents, and typographic quotes, dashes and
+// spaces as plain ones. GSM-7 has "Ç" but no "ç", which loses its
+// cedilla rather than become a capital: "Francois", not "FranÇois".
+var transliterations = map[rune]string{
+	'á': "a", 'â': "a", 'ã': "a", 'ă': "a", 'ą': "a", 'Á': "A", 'Â': "A", 'Ã': "A", 'À': "A", 'Ă': "A", 'Ą': "A",
+	'ç': "c", 'ć': "c", 'č': "c", 'Ć': "C", 'Č': "C",
+	'ď': "d", 'ð': "d", 'Ď': "D", 'Ð': "D",
+	'ê': "e", 'ë': "e", 'ę': "e", 'ě': "e", 'Ê': "E", 'Ë': "E", 'È': "E", 'Ę': "E", 'Ě': "E",
+	'ğ': "g", 'Ğ': "G",
+	'í': "i", 'î': "i", 'ï': "i", 'ı': "i", 'Í': "I", 'Î': "I", 'Ï': "I", 'Ì': "I", 'İ': "I",
+	'ł': "l", 'Ł': "L",
+	'ń': "n", 'ň': "n", 'Ń': "N", 'Ň': "N",
+	'ó': "o", 'ô': "o", 'õ': "o", 'ő': "ö", 'Ó': "O", 'Ô': "O", 'Õ': "O", 'Ò': "O", 'Ő': "Ö",
+	'œ': "oe", 'Œ': "OE",
+	'ř': "r", 'Ř': "R",
+	'ś': "s", 'š': "s", 'ș': "s", 'ş': "s", 'Ś': "S", 'Š': "S", 'Ș': "S", 'Ş': "S",
+	'ť': "t", 'ț': "t", 'ţ': "t", 'Ť': "T", 'Ț': "T", 'Ţ': "T", 'þ': "th", 'Þ': "Th",
+	'ú': "u", 'û': "u", 'ů': "u", 'ű': "ü", 'Ú': "U", 'Û': "U", 'Ù': "U", 'Ů': "U", 'Ű': "Ü",
+	'ý': "y", 'ÿ': "y", 'Ý': "Y", 'Ÿ': "Y",
+	'ź': "z", 'ż': "z", 'ž': "z", 'Ź': "Z", 'Ż': "Z", 'Ž': "Z",
+	'‘': "'", '’': "'", '‚': "'", '“': "\"", '”': "\"", '„': "\"", '«': "\"", '»': "\"",
+	'–': "-", '—': "-", '…': "...",
+	'\u00a0': " ", '\u2009': " ", '\u202f': " ",
+}
+
+// transliterate spells text in GSM-7, or reports false if it can't.
+// Combining accents that compose didn't compose away are dropped.
+func transliterate(text string) (string, bool) {
+	var b strings.Builder
+	for _, r := range text {
+		switch t, ok := transliterations[r]; {
+		case septets(r) != nil:
+			b.WriteRune(r)
+		case ok:
+			b.WriteString(t)
+		case r >= 0x0300 && r <= 0x036f:
+		default:
+			return "", false
+		}
+	}
+	return b.String(), true
+}
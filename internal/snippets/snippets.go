@@ -0,0 +1,257 @@
+// Package snippets type-checks the ```go blocks of a Markdown file.
+//
+// Most blocks are fragments, so each one is wrapped into a file first:
+// whole files are used as they are, declarations get a package clause and
+// statements also get a function around them. Imports of common standard
+// packages are added automatically. Identifiers a fragment takes for
+// granted are declared in a comment right before the block:
+//
+//	<!-- snippet-check
+//	var jobs []Job
+//	type Job struct{}
+//	func do(Job) error
+//	-->
+//
+// A comment holding just "skip" leaves the next block unchecked.
+package snippets
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// Block is one ```go fence.
+type Block struct {
+	Line  int // line of the first line of code
+	Code  string
+	Stubs string
+	Skip  bool
+}
+
+const commentStart = "<!-- snippet-check"
+
+// Extract returns the Go blocks of a Markdown document.
+func Extract(markdown []byte) []Block {
+	var (
+		blocks  []Block
+		pending *Block // stubs waiting for their block
+		inStubs bool
+		code    *Block
+		lineNo  int
+	)
+	sc := bufio.NewScanner(bytes.NewReader(markdown))
+	for sc.Scan() {
+		lineNo++
+		line := sc.Text()
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case code != nil:
+			if trimmed == "```" {
+				blocks = append(blocks, *code)
+				code = nil
+				continue
+			}
+			code.Code += line + "\n"
+		case inStubs:
+			if strings.HasSuffix(trimmed, "-->") {
+				inStubs = false
+				trimmed = strings.TrimSpace(strings.TrimSuffix(trimmed, "-->"))
+			}
+			addStub(pending, trimmed)
+		case strings.HasPrefix(trimmed, commentStart):
+			pending = &Block{}
+			rest := strings.TrimSpace(strings.TrimPrefix(trimmed, commentStart))
+			if strings.HasSuffix(rest, "-->") {
+				rest = strings.TrimSpace(strings.TrimSuffix(rest, "-->"))
+			} else {
+				inStubs = true
+			}
+			addStub(pending, rest)
+		case strings.HasPrefix(trimmed, "```"):
+			lang := strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+			if lang != "go" {
+				// Skip over other fences so their contents aren't
+				// mistaken for Markdown.
+				for sc.Scan() {
+					lineNo++
+					if strings.TrimSpace(sc.Text()) == "```" {
+						break
+					}
+				}
+				pending = nil
+				continue
+			}
+			code = &Block{Line: lineNo + 1}
+			if pending != nil {
+				code.Stubs, code.Skip = pending.Stubs, pending.Skip
+			}
+			pending = nil
+		case trimmed != "":
+			pending = nil
+		}
+	}
+	return blocks
+}
+
+func addStub(b *Block, line string) {
+	switch line {
+	case "":
+	case "skip":
+		b.Skip = true
+	default:
+		b.Stubs += line + "\n"
+	}
+}
+
+// packages maps the package names fragments use without importing them
+// to their import paths.
+var packages = map[string]string{
+	"atomic":  "sync/atomic",
+	"bufio":   "bufio",
+	"bytes":   "bytes",
+	"cmp":     "cmp",
+	"context": "context",
+	"embed":   "embed",
+	"errors":  "errors",
+	"fmt":     "fmt",
+	"http":    "net/http",
+	"io":      "io",
+	"json":    "encoding/json",
+	"log":     "log",
+	"os":      "os",
+	"sort":    "sort",
+	"sql":     "database/sql",
+	"strconv": "strconv",
+	"strings": "strings",
+	"sync":    "sync",
+	"testing": "testing",
+	"time":    "time",
+}
+
+// Problem is a type error in a block, positioned in the Markdown file.
+type Problem struct {
+	Pos token.Position
+	Msg string
+}
+
+func (p Problem) String() string { return fmt.Sprintf("%s: %s", p.Pos, p.Msg) }
+
+// Checker type-checks blocks. The zero value is not usable; call
+// NewChecker.
+type Checker struct {
+	importer types.Importer
+}
+
+// NewChecker returns a Checker that loads packages with imp.
+func NewChecker(imp types.Importer) *Checker {
+	return &Checker{importer: imp}
+}
+
+// Check type-checks b, which came from the Markdown file named file.
+// Unused variables and imports are fine in a snippet and aren't reported.
+func (c *Checker) Check(file string, b Block) []Problem {
+	if b.Skip {
+		return nil
+	}
+
+	var imports []string
+	for {
+		fset := token.NewFileSet()
+		src := wrap(file, b, imports)
+		f, err := parser.ParseFile(fset, "snippet.go", src, parser.ParseComments)
+		if err != nil {
+			return parseProblems(err)
+		}
+
+		var problems []Problem
+		missing := map[string]bool{}
+		conf := types.Config{
+			Importer: c.importer,
+			Error: func(err error) {
+				terr := err.(types.Error)
+				if terr.Soft {
+					return
+				}
+				name, ok := strings.CutPrefix(terr.Msg, "undefined: ")
+				if path := packages[name]; ok && path != "" {
+					missing[path] = true
+					return
+				}
+				problems = append(problems, Problem{Pos: fset.Position(terr.Pos), Msg: terr.Msg})
+			},
+		}
+		conf.Check("snippet", fset, []*ast.File{f}, nil)
+
+		if len(missing) == 0 || isFile(b.Code) {
+			for path := range missing {
+				problems = append(problems, Problem{Pos: token.Position{Filename: file, Line: b.Line}, Msg: "missing import " + path})
+			}
+			sort.Slice(problems, func(i, j int) bool { return problems[i].Pos.Line < problems[j].Pos.Line })
+			return problems
+		}
+		for path := range missing {
+			imports = append(imports, path)
+		}
+	}
+}
+
+// wrap turns b into a Go file whose //line directives point back at the
+// Markdown file.
+func wrap(file string, b Block, imports []string) string {
+	var src strings.Builder
+	code := fmt.Sprintf("//line %s:%d:1\n%s", file, b.Line, b.Code)
+	stubs := "\n//line stubs.go:1:1\n" + b.Stubs
+
+	if isFile(b.Code) {
+		return code + stubs
+	}
+	src.WriteString("package snippet\n\n")
+	sort.Strings(imports)
+	for _, path := range imports {
+		fmt.Fprintf(&src, "import %q\n", path)
+	}
+	if isDecls(b.Code) {
+		src.WriteString(code)
+	} else {
+		src.WriteString("func _() error {\n" + code + "\n//line wrapper.go:1:1\nreturn nil\n}\n")
+	}
+	src.WriteString(stubs)
+	return src.String()
+}
+
+func isFile(code string) bool {
+	for _, line := range strings.Split(code, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		return strings.HasPrefix(line, "package ")
+	}
+	return false
+}
+
+func isDecls(code string) bool {
+	_, err := parser.ParseFile(token.NewFileSet(), "", "package snippet\n"+code, 0)
+	return err == nil
+}
+
+func parseProblems(err error) []Problem {
+	list, ok := err.(scanner.ErrorList)
+	if !ok {
+		return []Problem{{Msg: err.Error()}}
+	}
+	var problems []Problem
+	for _, e := range list {
+		problems = append(problems, Problem{Pos: e.Pos, Msg: e.Msg})
+	}
+	return problems
+}
@@ -0,0 +1,102 @@
+package snippets
+
+import (
+	"go/importer"
+	"os"
+	"strings"
+	"testing"
+)
+
+const markdown = "# Notes\n" +
+	"\n" +
+	"<!-- snippet-check\n" +
+	"var names []string\n" +
+	"-->\n" +
+	"```go\n" +
+	"for _, n := range names {\n" +
+	"    fmt.Println(n)\n" +
+	"}\n" +
+	"```\n" +
+	"\n" +
+	"```bash\n" +
+	"go test ./...\n" +
+	"```\n" +
+	"\n" +
+	"```go\n" +
+	"type Greeter interface{ Greet() string }\n" +
+	"func Hello(g Greeter) string { return g.Greet() + missing }\n" +
+	"```\n" +
+	"\n" +
+	"<!-- snippet-check skip -->\n" +
+	"```go\n" +
+	"this is not Go\n" +
+	"```\n"
+
+func TestExtract(t *testing.T) {
+	blocks := Extract([]byte(markdown))
+	if len(blocks) != 3 {
+		t.Fatalf("got %d blocks want 3", len(blocks))
+	}
+
+	first := blocks[0]
+	if first.Line != 7 || first.Stubs != "var names []string\n" || !strings.HasPrefix(first.Code, "for _, n") {
+		t.Errorf("unexpected first block %+v", first)
+	}
+	if blocks[1].Stubs != "" || blocks[1].Skip {
+		t.Errorf("stubs leaked into the second block: %+v", blocks[1])
+	}
+	if !blocks[2].Skip {
+		t.Errorf("third block should be skipped")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	checker := NewChecker(importer.Default())
+	blocks := Extract([]byte(markdown))
+
+	t.Run("statements with stubs and an implicit import", func(t *testing.T) {
+		assertProblems(t, checker.Check("notes.md", blocks[0]), nil)
+	})
+	t.Run("problems point at the Markdown line", func(t *testing.T) {
+		want := []string{"notes.md:18:51: undefined: missing"}
+		assertProblems(t, checker.Check("notes.md", blocks[1]), want)
+	})
+	t.Run("skipped blocks", func(t *testing.T) {
+		assertProblems(t, checker.Check("notes.md", blocks[2]), nil)
+	})
+	t.Run("whole files", func(t *testing.T) {
+		b := Block{Line: 3, Code: "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(1 + \"a\") }\n"}
+		got := checker.Check("notes.md", b)
+		if len(got) != 1 || got[0].Pos.Line != 7 {
+			t.Errorf("got %v want one problem on line 7", got)
+		}
+	})
+}
+
+// TestREADME keeps the snippets in the repository README compiling.
+func TestREADME(t *testing.T) {
+	if testing.Short() {
+		t.Skip("type-checks the standard library")
+	}
+	markdown, err := os.ReadFile("../../README.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker := NewChecker(importer.Default())
+	for _, b := range Extract(markdown) {
+		for _, p := range checker.Check("README.md", b) {
+			t.Error(p)
+		}
+	}
+}
+
+func assertProblems(t testing.TB, got []Problem, want []string) {
+	t.Helper()
+	var msgs []string
+	for _, p := range got {
+		msgs = append(msgs, p.String())
+	}
+	if strings.Join(msgs, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got %q want %q", msgs, want)
+	}
+}
@@ -0,0 +1,81 @@
+package width
+
+import "unicode"
+
+// wide are the runes whose East_Asian_Width is W or F in Unicode 15.1,
+// less the combining and format characters among them.
+var wide = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x1100, 0x115f, 1}, {0x231a, 0x231b, 1}, {0x2329, 0x232a, 1},
+		{0x23e9, 0x23ec, 1}, {0x23f0, 0x23f3, 3}, {0x25fd, 0x25fe, 1},
+		{0x2614, 0x2615, 1}, {0x2648, 0x2653, 1}, {0x267f, 0x2693, 20},
+		{0x26a1, 0x26a1, 1}, {0x26aa, 0x26ab, 1}, {0x26bd, 0x26be, 1},
+		{0x26c4, 0x26c5, 1}, {0x26ce, 0x26d4, 6}, {0x26ea, 0x26ea, 1},
+		{0x26f2, 0x26f3, 1}, {0x26f5, 0x26fa, 5}, {0x26fd, 0x26fd, 1},
+		{0x2705, 0x2705, 1}, {0x270a, 0x270b, 1}, {0x2728, 0x2728, 1},
+		{0x274c, 0x274e, 2}, {0x2753, 0x2755, 1}, {0x2757, 0x2757, 1},
+		{0x2795, 0x2797, 1}, {0x27b0, 0x27bf, 15}, {0x2b1b, 0x2b1c, 1},
+		{0x2b50, 0x2b55, 5}, {0x2e80, 0x303e, 1}, {0x3041, 0x33ff, 1},
+		{0x3400, 0x4dbf, 1}, {0x4e00, 0x9fff, 1}, {0xa000, 0xa4cf, 1},
+		{0xa960, 0xa97f, 1}, {0xac00, 0xd7a3, 1}, {0xf900, 0xfaff, 1},
+		{0xfe10, 0xfe19, 1}, {0xfe30, 0xfe6f, 1}, {0xff00, 0xff60, 1},
+		{0xffe0, 0xffe6, 1},
+	},
+	R32: []unicode.Range32{
+		{0x16fe0, 0x16fe4, 1}, {0x17000, 0x18aff, 1}, {0x1b000, 0x1b2ff, 1},
+		{0x1f004, 0x1f004, 1}, {0x1f0cf, 0x1f0cf, 1}, {0x1f18e, 0x1f18e, 1},
+		{0x1f191, 0x1f19a, 1}, {0x1f200, 0x1f251, 1}, {0x1f300, 0x1f320, 1},
+		{0x1f32d, 0x1f335, 1}, {0x1f337, 0x1f37c, 1}, {0x1f37e, 0x1f393, 1},
+		{0x1f3a0, 0x1f3ca, 1}, {0x1f3cf, 0x1f3d3, 1}, {0x1f3e0, 0x1f3f0, 1},
+		{0x1f3f4, 0x1f3f4, 1}, {0x1f3f8, 0x1f43e, 1}, {0x1f440, 0x1f440, 1},
+		{0x1f442, 0x1f4fc, 1}, {0x1f4ff, 0x1f53d, 1}, {0x1f54b, 0x1f54e, 1},
+		{0x1f550, 0x1f567, 1}, {0x1f57a, 0x1f57a, 1}, {0x1f595, 0x1f596, 1},
+		{0x1f5a4, 0x1f5a4, 1}, {0x1f5fb, 0x1f64f, 1}, {0x1f680, 0x1f6c5, 1},
+		{0x1f6cc, 0x1f6cc, 1}, {0x1f6d0, 0x1f6d2, 1}, {0x1f6d5, 0x1f6d7, 1},
+		{0x1f6dc, 0x1f6df, 1}, {0x1f6eb, 0x1f6ec, 1}, {0x1f6f4, 0x1f6fc, 1},
+		{0x1f7e0, 0x1f7eb, 1}, {0x1f7f0, 0x1f7f0, 1}, {0x1f90c, 0x1f93a, 1},
+		{0x1f93c, 0x1f945, 1}, {0x1f947, 0x1f9ff, 1}, {0x1fa70, 0x1faff, 1},
+		{0x20000, 0x2fffd, 1}, {0x30000, 0x3fffd, 1},
+	},
+}
+
+// ambiguous are the commonest runes whose East_Asian_Width is A: Latin-1
+// symbols, Greek, Cyrillic, general punctuation, arrows, maths, box
+// drawing and geometric shapes.
+var ambiguous = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x00a1, 0x00a4, 3}, {0x00a7, 0x00a8, 1}, {0x00aa, 0x00aa, 1},
+		{0x00ae, 0x00ae, 1}, {0x00b0, 0x00b4, 1}, {0x00b6, 0x00ba, 1},
+		{0x00bc, 0x00bf, 1}, {0x00c6, 0x00d0, 10}, {0x00d7, 0x00d8, 1},
+		{0x00de, 0x00e1, 1}, {0x00e6, 0x00e6, 1}, {0x00e8, 0x00ea, 1},
+		{0x00ec, 0x00ed, 1}, {0x00f0, 0x00f0, 1}, {0x00f2, 0x00f3, 1},
+		{0x00f7, 0x00fa, 1}, {0x00fc, 0x00fe, 2}, {0x0391, 0x03a1, 1},
+		{0x03a3, 0x03a9, 1}, {0x03b1, 0x03c1, 1}, {0x03c3, 0x03c9, 1},
+		{0x0401, 0x0401, 1}, {0x0410, 0x044f, 1}, {0x0451, 0x0451, 1},
+		{0x2010, 0x2010, 1}, {0x2013, 0x2016, 1}, {0x2018, 0x2019, 1},
+		{0x201c, 0x201d, 1}, {0x2020, 0x2022, 1}, {0x2024, 0x2027, 1},
+		{0x2030, 0x2030, 1}, {0x2032, 0x2033, 1}, {0x2035, 0x203b, 6},
+		{0x203e, 0x203e, 1}, {0x2103, 0x2105, 2}, {0x2109, 0x2109, 1},
+		{0x2113, 0x2116, 3}, {0x2121, 0x2122, 1}, {0x2126, 0x212b, 5},
+		{0x2153, 0x2154, 1}, {0x215b, 0x215e, 1}, {0x2160, 0x216b, 1},
+		{0x2170, 0x2179, 1}, {0x2190, 0x2199, 1}, {0x21d2, 0x21d4, 2},
+		{0x2200, 0x2200, 1},
+		{0x2202, 0x2203, 1}, {0x2207, 0x2208, 1}, {0x220b, 0x220b, 1},
+		{0x220f, 0x2211, 2}, {0x2215, 0x221a, 5}, {0x221d, 0x2220, 1},
+		{0x2223, 0x2225, 2}, {0x2227, 0x222c, 1}, {0x222e, 0x222e, 1},
+		{0x2234, 0x2237, 1}, {0x223c, 0x223d, 1}, {0x2248, 0x224c, 4},
+		{0x2252, 0x2252, 1}, {0x2260, 0x2261, 1}, {0x2264, 0x2267, 1},
+		{0x226a, 0x226b, 1}, {0x226e, 0x226f, 1}, {0x2282, 0x2283, 1},
+		{0x2286, 0x2287, 1}, {0x2295, 0x2299, 4}, {0x22a5, 0x22a5, 1},
+		{0x22bf, 0x22bf, 1}, {0x2312, 0x2312, 1}, {0x2460, 0x24e9, 1},
+		{0x24eb, 0x254b, 1}, {0x2550, 0x2573, 1}, {0x2580, 0x258f, 1},
+		{0x2592, 0x2595, 1}, {0x25a0, 0x25a1, 1}, {0x25a3, 0x25a9, 1},
+		{0x25b2, 0x25b3, 1}, {0x25b6, 0x25b7, 1}, {0x25bc, 0x25bd, 1},
+		{0x25c0, 0x25c1, 1}, {0x25c6, 0x25c8, 1}, {0x25cb, 0x25cb, 1},
+		{0x25ce, 0x25d1, 1}, {0x25e2, 0x25e5, 1}, {0x25ef, 0x25ef, 1},
+		{0x2605, 0x2606, 1}, {0x2609, 0x2609, 1}, {0x260e, 0x260f, 1},
+		{0x261c, 0x261e, 2}, {0x2640, 0x2642, 2}, {0x2660, 0x2661, 1},
+		{0x2663, 0x2665, 1}, {0x2667, 0x266a, 1}, {0x266c, 0x266d, 1},
+		{0x266f, 0x266f, 1}, {0xe000, 0xf8ff, 1}, {0xfffd, 0xfffd, 1},
+	},
+}
@@ -0,0 +1,101 @@
+// Package width measures text in terminal and fixed-width cells, by the
+// East_Asian_Width property of Unicode's UAX #11. Wide and fullwidth
+// characters, such as CJK ideographs and most emoji, take two cells;
+// combining marks and format characters none. Text is measured a
+// grapheme cluster at a time, so an emoji sequence counts once.
+//
+// Ambiguous characters, such as Greek and Cyrillic letters or "…", are
+// narrow in most text but wide in East Asian fonts: set EastAsian to
+// measure them the way a Chinese, Japanese or Korean display does.
+package width
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"learn-go/internal/grapheme"
+)
+
+// Measure measures text for one kind of display.
+type Measure struct {
+	// EastAsian counts ambiguous characters as wide.
+	EastAsian bool
+}
+
+// Rune returns the cells r takes on its own: 0, 1 or 2.
+func (m Measure) Rune(r rune) int {
+	switch {
+	case r == 0 || r == 0x200b || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf, unicode.Cc):
+		return 0
+	case r >= 0x1160 && r <= 0x11ff:
+		// Hangul medial vowels and final consonants join the syllable.
+		return 0
+	case unicode.Is(wide, r):
+		return 2
+	case m.EastAsian && unicode.Is(ambiguous, r):
+		return 2
+	}
+	return 1
+}
+
+// Cluster returns the cells a grapheme cluster takes: what its first rune
+// takes, except that an emoji followed by the emoji variation selector
+// and a pair of regional indicators (a flag) are wide.
+func (m Measure) Cluster(c string) int {
+	r, size := utf8.DecodeRuneInString(c)
+	w := m.Rune(r)
+	switch {
+	case r >= 0x1f1e6 && r <= 0x1f1ff:
+		return 2 // a flag, or a lone letter drawn like one
+	case strings.ContainsRune(c[size:], 0xfe0f):
+		return 2
+	}
+	return w
+}
+
+// String returns the cells s takes.
+func (m Measure) String(s string) int {
+	n := 0
+	for s != "" {
+		var c string
+		c, s = grapheme.First(s)
+		n += m.Cluster(c)
+	}
+	return n
+}
+
+// Truncate shortens s to at most cells cells, never splitting a grapheme
+// cluster. If s has to be shortened, tail, such as "…", takes the place
+// of what was cut, unless even tail doesn't fit.
+func (m Measure) Truncate(s string, cells int, tail string) string {
+	if m.String(s) <= cells {
+		return s
+	}
+	room := cells - m.String(tail)
+	if room < 0 {
+		room, tail = cells, ""
+	}
+	var b strings.Builder
+	for s != "" {
+		var c string
+		c, s = grapheme.First(s)
+		w := m.Cluster(c)
+		if w > room {
+			break
+		}
+		b.WriteString(c)
+		room -= w
+	}
+	return b.String() + tail
+}
+
+// String returns the cells s takes on a display that isn't East Asian.
+func String(s string) int {
+	return Measure{}.String(s)
+}
+
+// Truncate is Measure.Truncate for a display that isn't East Asian.
+func Truncate(s string, cells int, tail string) string {
+	return Measure{}.Truncate(s, cells, tail)
+}
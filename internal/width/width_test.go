@@ -0,0 +1,77 @@
+package width
+
+import "testing"
+
+func TestString(t *testing.T) {
+	cases := []struct {
+		s                 string
+		narrow, eastAsian int
+	}{
+		{"", 0, 0},
+		{"Max", 3, 3},
+		{"Élodie", 6, 6},
+		{"E\u0301lodie", 6, 6},
+		{"你好", 4, 4},
+		{"こんにちは、Max", 15, 15},
+		{"ＡＢ", 4, 4},
+		{"ｱｲ", 2, 2}, // halfwidth katakana
+		{"한국어", 6, 6},
+		{"\u1112\u1161\u11ab", 2, 2}, // 한 as jamo
+		{"Привет", 6, 12},
+		{"Γεια", 4, 8},
+		{"…", 1, 2},
+		{"👋", 2, 2},
+		{"👋🏽", 2, 2},
+		{"👨\u200d👩\u200d👧", 2, 2},
+		{"🇫🇷", 2, 2},
+		{"❤", 1, 1},
+		{"❤\ufe0f", 2, 2},
+		{"a\u200bb", 2, 2},
+		{"नमस्ते", 3, 3}, // स्ते is one cluster
+		{"\U00020000", 2, 2},
+	}
+	for _, c := range cases {
+		if got := String(c.s); got != c.narrow {
+			t.Errorf("String(%q): got %d want %d", c.s, got, c.narrow)
+		}
+		if got := (Measure{EastAsian: true}).String(c.s); got != c.eastAsian {
+			t.Errorf("East Asian String(%q): got %d want %d", c.s, got, c.eastAsian)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	cases := []struct {
+		s     string
+		cells int
+		tail  string
+		want  string
+	}{
+		{"Maximilian", 20, "…", "Maximilian"},
+		{"Maximilian", 10, "…", "Maximilian"},
+		{"Maximilian", 6, "…", "Maxim…"},
+		{"Maximilian", 6, "...", "Max..."},
+		{"E\u0301lodie", 3, "…", "E\u0301l…"},
+		{"你好世界", 5, "…", "你好…"},
+		{"你好世界", 4, "…", "你…"},
+		{"👨\u200d👩\u200d👧 family", 3, "…", "👨\u200d👩\u200d👧…"},
+		{"👨\u200d👩\u200d👧 family", 2, "…", "…"},
+		{"🇫🇷🇯🇵🇩🇪", 5, "…", "🇫🇷🇯🇵…"},
+		{"Maximilian", 2, "...", "Ma"}, // no room for the tail
+		{"Maximilian", 0, "…", ""},
+	}
+	for _, c := range cases {
+		got := Truncate(c.s, c.cells, c.tail)
+		if got != c.want {
+			t.Errorf("Truncate(%q, %d, %q): got %q want %q", c.s, c.cells, c.tail, got, c.want)
+		}
+		if w := String(got); w > c.cells {
+			t.Errorf("Truncate(%q, %d, %q) takes %d cells", c.s, c.cells, c.tail, w)
+		}
+	}
+
+	// "…" is wide on an East Asian display.
+	if got := (Measure{EastAsian: true}).Truncate("你好世界", 5, "…"); got != "你…" {
+		t.Errorf("East Asian: got %q", got)
+	}
+}